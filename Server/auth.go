@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys of the credentials clients send with every call.
+const (
+	usernameKey = "username"
+	passwordKey = "password"
+)
+
+// Names of the authentication modes accepted by newAuthenticator.
+const (
+	noAuth         = "none"
+	sharedSecret   = "secret"
+	credentialFile = "file"
+)
+
+// Checks the credentials sent with calls.
+type authenticator interface {
+	// Reports whether the credentials are valid, and whether they identify the
+	// participant, in which case the username is to be used as callsign.
+	authenticate(username string, password string) (ok bool, identifies bool)
+}
+
+// Obtains the authenticator for a mode given on the command line.
+// Returns nil for no authentication.
+func newAuthenticator(mode string, secret string, file string) (authenticator, error) {
+	switch mode {
+	case noAuth:
+		return nil, nil
+	case sharedSecret:
+		if secret == "" {
+			return nil, fmt.Errorf("auth mode '%s' needs a secret", sharedSecret)
+		}
+		return secretAuthenticator{secret: []byte(secret)}, nil
+	case credentialFile:
+		return loadCredentialFile(file)
+	default:
+		return nil, fmt.Errorf("unknown auth mode '%s' (want %s, %s or %s)", mode, noAuth, sharedSecret, credentialFile)
+	}
+}
+
+// Accepts any callsign, as long as the password is the secret shared by all participants.
+type secretAuthenticator struct {
+	secret []byte
+}
+
+func (a secretAuthenticator) authenticate(username string, password string) (bool, bool) {
+	return subtle.ConstantTimeCompare([]byte(password), a.secret) == 1, false
+}
+
+// Accepts the users listed in a credential file, with their bcrypt password hashes.
+// Each line of the file is username:hash. Empty lines and lines starting with # are skipped.
+// Lines can be generated by running the server with -hash-password.
+type fileAuthenticator struct {
+	hashes map[string][]byte
+	dummy  []byte // Compared against for unknown users, so they take as long as wrong passwords.
+}
+
+// Reads a credential file.
+func loadCredentialFile(path string) (fileAuthenticator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return fileAuthenticator{}, err
+	}
+	defer file.Close()
+
+	hashes := make(map[string][]byte)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		username, hash, found := strings.Cut(text, ":")
+		if !found || username == "" {
+			return fileAuthenticator{}, fmt.Errorf("%s:%d: want username:hash", path, line)
+		}
+		hashes[username] = []byte(hash)
+	}
+	if err := scanner.Err(); err != nil {
+		return fileAuthenticator{}, err
+	}
+	dummy, err := bcrypt.GenerateFromPassword([]byte(newToken()), bcrypt.DefaultCost)
+	return fileAuthenticator{hashes: hashes, dummy: dummy}, err
+}
+
+func (a fileAuthenticator) authenticate(username string, password string) (bool, bool) {
+	hash, ok := a.hashes[username]
+	if !ok {
+		bcrypt.CompareHashAndPassword(a.dummy, []byte(password))
+		return false, true
+	}
+	return bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil, true
+}
+
+// Formats a credential file line for a user, with a bcrypt hash of the password.
+func hashPasswordLine(username string, password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return username + ":" + string(hash), nil
+}
+
+// Reads a password from standard input and prints the credential file line for a user.
+func printCredentialLine(username string) {
+	fmt.Fprintf(os.Stderr, "Password for %s: ", username)
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Scan()
+	line, err := hashPasswordLine(username, scanner.Text())
+	if err != nil {
+		log.Fatalf("failed to hash password: %v\n", err)
+	}
+	fmt.Println(line)
+}
+
+// Context key for the callsign established by authentication.
+type authenticatedNameKey struct{}
+
+// Obtains the callsign established by authentication of the call, if any.
+func authenticatedName(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(authenticatedNameKey{}).(string)
+	return name, ok
+}
+
+// Checks the credentials in the metadata of a call. Returns the context for handling it,
+// carrying the authenticated callsign if the credentials identify the participant.
+func (s *ChittyChatServer) authenticateCall(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	username, password := firstValue(md, usernameKey), firstValue(md, passwordKey)
+	ok, identifies := s.auth.authenticate(username, password)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Invalid credentials.")
+	}
+	if identifies {
+		ctx = context.WithValue(ctx, authenticatedNameKey{}, username)
+	}
+	return ctx, nil
+}
+
+// Server interceptor authenticating unary calls.
+func (s *ChittyChatServer) unaryAuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authenticateCall(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// Server interceptor authenticating streaming calls.
+func (s *ChittyChatServer) streamAuthInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticateCall(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// A server stream with the context established by authentication.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// Obtains the first value for a metadata key, or "" if there is none.
+func firstValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	proto "example/chittychat/grpc"
+	"log"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The client chats on a single stream. Method returns when the stream terminates.
+// The first request must join; it is handled as JoinMessageBoard handles its confirm,
+// and the messages of the chat are sent as responses. Once the client has joined, its
+// posts are broadcast as PostMessage does, and each is acknowledged on the stream.
+// The session token is sent in the response header all the same, for the other calls.
+// Posts are handled until the method returns, and no acknowledgement is sent after it has.
+func (s *ChittyChatServer) Chat(stream grpc.BidiStreamingServer[proto.ChatRequest, proto.ChatResponse]) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if first.GetJoin() == nil {
+		return status.Error(codes.InvalidArgument, "Join the message board with the first request.")
+	}
+	feed := &chatFeed{BidiStreamingServer: stream}
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	var done chan struct{} // Closed once posts are no longer handled. Nil until joined.
+	err = s.joinMessageBoard(first.GetJoin(), feed, func(sess *session) {
+		done = make(chan struct{})
+		requests := make(chan *proto.ChatRequest)
+		go receiveRequests(ctx, feed, requests)
+		go func() {
+			defer close(done)
+			s.receivePosts(ctx, sess, feed, requests)
+		}()
+	})
+	cancel()
+	if done != nil {
+		<-done
+	}
+	return err
+}
+
+// Go-routine receiving the requests a client sends on its Chat stream, and passing them on
+// until the stream ends, when the channel is closed, or the context is done.
+func receiveRequests(ctx context.Context, feed *chatFeed, requests chan<- *proto.ChatRequest) {
+	defer close(requests)
+	for {
+		request, err := feed.Recv()
+		if err != nil {
+			return
+		}
+		select {
+		case requests <- request:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Posts the messages a client sends on its Chat stream under its session, and acknowledges
+// each, until the stream ends or the context is done.
+func (s *ChittyChatServer) receivePosts(ctx context.Context, sess *session, feed *chatFeed, requests <-chan *proto.ChatRequest) {
+	for {
+		var request *proto.ChatRequest
+		select {
+		case r, ok := <-requests:
+			if !ok {
+				return
+			}
+			request = r
+		case <-ctx.Done():
+			return
+		}
+		var confirm *proto.Confirm
+		var err error
+		s.mu.Lock()
+		if request.GetPost() == nil {
+			err = status.Error(codes.InvalidArgument, "Already joined. Only post messages on the stream.")
+		} else if s.sessions[sess.token] != sess {
+			err = status.Error(codes.Unauthenticated, "No session. Join the message board first.")
+		} else {
+			confirm, err = s.post(sess, request.GetPost())
+		}
+		s.mu.Unlock()
+
+		ack := &proto.ChatAck{Id: request.Id, Confirm: confirm}
+		if err != nil {
+			log.Printf("Chat: Post %d from '%s' failed: %v\n", request.Id, sess.callsign, err)
+			ack.Code = int32(status.Code(err))
+			ack.Error = status.Convert(err).Message()
+		}
+		err = feed.send(&proto.ChatResponse{Kind: &proto.ChatResponse_Ack{Ack: ack}})
+		if err != nil {
+			return
+		}
+	}
+}
+
+// A Chat stream, presented as the stream of messages JoinMessageBoard sends, so that
+// joining and streaming the chat work the same for both.
+// Messages and acknowledgements are sent from different go-routines, one at a time.
+type chatFeed struct {
+	grpc.BidiStreamingServer[proto.ChatRequest, proto.ChatResponse]
+	mu sync.Mutex
+}
+
+// Sends a message of the chat.
+func (f *chatFeed) Send(message *proto.Message) error {
+	return f.send(&proto.ChatResponse{Kind: &proto.ChatResponse_Message{Message: message}})
+}
+
+// Sends a response.
+func (f *chatFeed) send(response *proto.ChatResponse) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.BidiStreamingServer.Send(response)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	proto "example/chittychat/grpc"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Maximum number of characters in a message id.
+const maxMessageID = 64
+
+// Remembers the confirms of the last posts of each author by message id, so that a post
+// sent again, e.g. after the connection broke before it was confirmed, is not broadcast twice.
+// Windows are kept by callsign rather than session, so they last across reconnects.
+// It is guarded by s.mu.
+type dedupWindow struct {
+	size    int // Number of ids remembered per author. Nothing is remembered if 0.
+	authors map[string]*authorWindow
+}
+
+// The ids of an author's last posts, and their confirms.
+type authorWindow struct {
+	confirms map[string]*proto.Confirm // By message id.
+	order    []string                  // Message ids, oldest first.
+}
+
+// Obtains an empty window.
+func newDedupWindow(size int) *dedupWindow {
+	return &dedupWindow{
+		size:    size,
+		authors: make(map[string]*authorWindow),
+	}
+}
+
+// Obtains the confirm of an author's post with a message id, or nil if there is none in the window.
+func (w *dedupWindow) lookup(author string, id string) *proto.Confirm {
+	window := w.authors[author]
+	if id == "" || window == nil {
+		return nil
+	}
+	return window.confirms[id]
+}
+
+// Remembers the confirm of an author's post with a message id, forgetting the author's
+// oldest one if the window is full.
+func (w *dedupWindow) remember(author string, id string, confirm *proto.Confirm) {
+	if id == "" || w.size <= 0 {
+		return
+	}
+	window := w.authors[author]
+	if window == nil {
+		window = &authorWindow{confirms: make(map[string]*proto.Confirm)}
+		w.authors[author] = window
+	}
+	if window.confirms[id] == nil {
+		if len(window.order) == w.size {
+			delete(window.confirms, window.order[0])
+			window.order = window.order[1:]
+		}
+		window.order = append(window.order, id)
+	}
+	window.confirms[id] = confirm
+}
+
+// Checks the message id of a post. Returns a codes.InvalidArgument status error if it is too long.
+func checkMessageID(id string) error {
+	if utf8.RuneCountInString(id) > maxMessageID {
+		return status.Errorf(codes.InvalidArgument, "Message ids have at most %d characters.", maxMessageID)
+	}
+	return nil
+}
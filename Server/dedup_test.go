@@ -0,0 +1,223 @@
+package main
+
+import (
+	proto "example/chittychat/grpc"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "google.golang.org/protobuf/proto"
+)
+
+// Counts the broadcasts with a message id among a room's recent messages.
+func broadcastsOf(s *ChittyChatServer, name string, id string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, message := range s.rooms[name].recent.messages {
+		if message.MessageId == id {
+			count++
+		}
+	}
+	return count
+}
+
+// Checks that a post sent again got the original confirm, and was broadcast only once.
+func checkDeduplicated(t *testing.T, s *ChittyChatServer, id string, first *proto.Confirm, again *proto.Confirm) {
+	t.Helper()
+	if !pb.Equal(first, again) {
+		t.Fatalf("got %v for message %s sent again, want %v", again, id, first)
+	}
+	if n := broadcastsOf(s, defaultRoom, id); n != 1 {
+		t.Fatalf("message %s broadcast %d time(s), want once", id, n)
+	}
+}
+
+func TestDuplicateOnSession(t *testing.T) {
+	s := newTestServer()
+	client := serveTest(t, s)
+	p, err := joinTest(t, client, "alice", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := p.post(client, "hello", "m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := p.post(client, "hello", "m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDeduplicated(t, s, "m1", first, again)
+
+	// The stream carries the post once, and the next one right after it.
+	_, err = p.post(client, "bye", "m2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	received, err := p.receiveUntil(lastSequence(s, defaultRoom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, message := range received {
+		if isChatText(message) {
+			texts = append(texts, message.GetContent())
+		}
+	}
+	if fmt.Sprint(texts) != "[hello bye]" {
+		t.Fatalf("got posts %v, want [hello bye]", texts)
+	}
+}
+
+// A post whose confirm was lost with the stream is sent again on the next one.
+func TestDuplicateAfterRejoin(t *testing.T) {
+	s := newTestServer()
+	client := serveTest(t, s)
+	p, err := joinTest(t, client, "alice", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := p.post(client, "hello", "m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.cancel()
+	deadline := time.Now().Add(testTimeout)
+	for {
+		s.mu.Lock()
+		broken := s.callsigns["alice"].client == nil
+		s.mu.Unlock()
+		if broken {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the server did not notice the stream break")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rejoined, err := joinTest(t, client, "alice", p.token, p.welcome.Sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := rejoined.post(client, "hello", "m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDeduplicated(t, s, "m1", first, again)
+}
+
+// A post logged before a restart is confirmed again, not broadcast again, after a restore.
+func TestDuplicateAfterRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chitty.wal")
+	before := newTestServer()
+	var err error
+	before.wal, err = openMessageLog(path, syncAlways, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := serveTest(t, before)
+	p, err := joinTest(t, client, "alice", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := p.post(client, "hello", "m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = before.wal.close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after := newTestServer()
+	after.wal, err = openMessageLog(path, syncAlways, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { after.wal.close() })
+	err = after.restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = serveTest(t, after)
+	p, err = joinTest(t, client, "alice", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := p.post(client, "hello", "m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDeduplicated(t, after, "m1", first, again)
+}
+
+// Only the last -dedup-window ids of an author are remembered; an older one is broadcast again.
+func TestDuplicateOutsideWindow(t *testing.T) {
+	s := newTestServer()
+	s.dedup = newDedupWindow(2)
+	client := serveTest(t, s)
+	p, err := joinTest(t, client, "alice", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	confirms := make(map[string]*proto.Confirm)
+	for _, id := range []string{"m1", "m2", "m3"} {
+		confirms[id], err = p.post(client, "hello "+id, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, id := range []string{"m2", "m3"} {
+		again, err := p.post(client, "hello "+id, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkDeduplicated(t, s, id, confirms[id], again)
+	}
+	again, err := p.post(client, "hello m1", "m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.Equal(again, confirms["m1"]) {
+		t.Fatalf("got the original confirm for m1, which should have left the window")
+	}
+	if n := broadcastsOf(s, defaultRoom, "m1"); n != 2 {
+		t.Fatalf("m1 broadcast %d time(s), want twice", n)
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	w := newDedupWindow(2)
+	for _, id := range []string{"m1", "m2", "m2", "m3"} {
+		w.remember("alice", id, &proto.Confirm{Author: id})
+	}
+	w.remember("bob", "m1", &proto.Confirm{Author: "bob"})
+	tests := []struct {
+		author string
+		id     string
+		want   string // Author of the confirm, empty if there is none.
+	}{
+		{"alice", "m1", ""},
+		{"alice", "m2", "m2"},
+		{"alice", "m3", "m3"},
+		{"bob", "m1", "bob"},
+		{"carol", "m1", ""},
+		{"alice", "", ""},
+	}
+	for _, test := range tests {
+		if got := w.lookup(test.author, test.id).GetAuthor(); got != test.want {
+			t.Errorf("lookup(%q, %q) got confirm of %q, want %q", test.author, test.id, got, test.want)
+		}
+	}
+
+	off := newDedupWindow(0)
+	off.remember("alice", "m1", &proto.Confirm{})
+	if off.lookup("alice", "m1") != nil {
+		t.Errorf("a window of size 0 remembered m1")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"example/chittychat/clock"
+	proto "example/chittychat/grpc"
+	"log"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "google.golang.org/protobuf/proto"
+)
+
+// Sends a message to one participant. It is queued in the feed of the recipient,
+// and of the sender as an echo, instead of being broadcast.
+// The call must carry a session token, and the message is sent under its callsign.
+//
+// Direct messages carry a Lamport timestamp only, assigned after the sender's clock and
+// the clock of the recipient's room. A vector entry would make others in the room wait
+// for a message they never receive. Nor do direct messages get sequence numbers, or go
+// into the history of recent messages, so a resuming client does not get them again.
+func (s *ChittyChatServer) SendDirectMessage(ctx context.Context, in *proto.Message) (*proto.Confirm, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, err := s.sessionOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	in.Author = sess.callsign
+	if !isChatText(in) {
+		return nil, status.Error(codes.InvalidArgument, "Only chat text can be sent.")
+	}
+	if utf8.RuneCountInString(in.GetContent()) > s.maxLength {
+		return nil, status.Error(codes.Aborted, "Content too long!")
+	}
+	recipient := s.callsigns[in.Recipient]
+	if recipient == nil || s.expired(recipient) {
+		return nil, status.Errorf(codes.NotFound, "There is no participant '%s'.", in.Recipient)
+	}
+	if recipient.client == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "'%s' is offline.", in.Recipient)
+	}
+
+	log.Printf("SendDirectMessage: %v\n", in)
+	sess.room.setTime(in.Timestamp())
+	recipient.room.setTime(clock.Timestamp{Lamport: in.LamportTs})
+	time := recipient.room.getTime()
+	sess.room.setTime(clock.Timestamp{Lamport: time.Lamport})
+	message := &proto.Message{
+		Payload:   &proto.Message_Content{Content: in.GetContent()},
+		Author:    in.Author,
+		Recipient: recipient.callsign,
+		LamportTs: time.Lamport,
+		Room:      recipient.room.name,
+	}
+	s.queueMessage(recipient.client, message)
+	if sess.client != nil && sess != recipient {
+		echo := pb.Clone(message).(*proto.Message)
+		echo.Room = sess.room.name
+		s.queueMessage(sess.client, echo)
+	}
+
+	return &proto.Confirm{
+		Author:    s.name,
+		LamportTs: sess.room.getTime().Lamport,
+		Room:      sess.room.name,
+	}, nil
+}
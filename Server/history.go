@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	proto "example/chittychat/grpc"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// A bounded buffer of the most recent broadcasts, oldest first.
+// Messages are kept as broadcast, with their sequence numbers and timestamps.
+type history struct {
+	messages []*proto.Message
+	limit    int
+}
+
+// Obtains an empty history keeping at most limit messages.
+func newHistory(limit int) *history {
+	return &history{limit: limit}
+}
+
+// Records a broadcast message, forgetting the oldest if the history is full.
+func (h *history) add(message *proto.Message) {
+	if h.limit <= 0 {
+		return
+	}
+	if len(h.messages) == h.limit {
+		h.messages = append(h.messages[:0], h.messages[1:]...)
+	}
+	h.messages = append(h.messages, message)
+}
+
+// Returns the messages broadcast after the given sequence number, oldest first, as far
+// back as the history goes.
+func (h *history) since(sequence uint64) []*proto.Message {
+	for i, message := range h.messages {
+		if message.Sequence > sequence {
+			return h.messages[i:]
+		}
+	}
+	return nil
+}
+
+// Returns the last n messages, oldest first, or all of them if there are fewer.
+func (h *history) last(n int) []*proto.Message {
+	if n > len(h.messages) {
+		n = len(h.messages)
+	}
+	if n <= 0 {
+		return nil
+	}
+	return h.messages[len(h.messages)-n:]
+}
+
+// Page sizes of GetHistory.
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// Obtains a page of earlier messages of a room, from the search index, which keeps many
+// more than the room's recent messages: every broadcast, up to -index-size.
+// Pages go backwards in time: the first holds the newest messages matching the filters,
+// and its cursor continues with older ones.
+func (s *ChittyChatServer) GetHistory(ctx context.Context, in *proto.HistoryRequest) (*proto.HistoryPage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, err := s.roomNamed(in.Room)
+	if err != nil {
+		return nil, err
+	}
+	before := uint64(math.MaxUint64)
+	if in.Cursor != "" {
+		before, err = decodeCursor(in.Cursor, r.name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	size := int(in.PageSize)
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	size = min(size, maxPageSize)
+
+	// Collect one more than fits, to know whether there is another page.
+	var matches []*proto.Message
+	// Messages are indexed in sequence order, so the page starts right before the cursor.
+	docs := s.index.rooms[r.name]
+	start := sort.Search(len(docs), func(i int) bool {
+		return s.index.message(docs[i]).Sequence >= before
+	})
+	for i := start - 1; i >= 0 && len(matches) <= size; i-- {
+		message := s.index.message(docs[i])
+		if matchesHistoryRequest(message, in) {
+			matches = append(matches, message)
+		}
+	}
+	page := &proto.HistoryPage{}
+	if len(matches) > size {
+		matches = matches[:size]
+		page.NextCursor = encodeCursor(r.name, matches[size-1].Sequence)
+	}
+	slices.Reverse(matches)
+	page.Messages = matches
+	return page, nil
+}
+
+// Reports whether a message passes the filters of a history request.
+func matchesHistoryRequest(message *proto.Message, in *proto.HistoryRequest) bool {
+	return (in.Author == "" || message.Author == in.Author) &&
+		(in.MinLamport == 0 || message.LamportTs >= in.MinLamport) &&
+		(in.MaxLamport == 0 || message.LamportTs <= in.MaxLamport) &&
+		(in.MinSequence == 0 || message.Sequence >= in.MinSequence) &&
+		(in.MaxSequence == 0 || message.Sequence <= in.MaxSequence)
+}
+
+// Encodes a history cursor: the room, and the sequence number to continue before.
+// Clients are to treat it as opaque.
+func encodeCursor(room string, before uint64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(before, 10) + ":" + room))
+}
+
+// Decodes a history cursor for a room. Returns a codes.InvalidArgument status error
+// if it is not a cursor, or is for another room.
+func decodeCursor(cursor string, room string) (uint64, error) {
+	invalid := status.Error(codes.InvalidArgument, "Invalid cursor.")
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, invalid
+	}
+	before, cursorRoom, found := strings.Cut(string(data), ":")
+	if !found || cursorRoom != room {
+		return 0, invalid
+	}
+	sequence, err := strconv.ParseUint(before, 10, 64)
+	if err != nil {
+		return 0, invalid
+	}
+	return sequence, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	proto "example/chittychat/grpc"
+	"fmt"
+	"testing"
+)
+
+// Paging through the history reaches back past the room's recent messages, to the first post.
+func TestHistoryPages(t *testing.T) {
+	const posts = 20
+	s := newTestServer()
+	s.resumeBuffer = 5
+	s.rooms[defaultRoom] = s.newRoom(defaultRoom)
+	client := serveTest(t, s)
+	p, err := joinTest(t, client, "alice", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 0; n < posts; n++ {
+		_, err = p.post(client, fmt.Sprintf("post %d", n), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	request := &proto.HistoryRequest{Room: defaultRoom, Author: "alice", PageSize: 7}
+	for pages := 0; ; pages++ {
+		if pages > posts {
+			t.Fatal("the history does not end")
+		}
+		page, err := client.GetHistory(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var texts []string
+		for _, message := range page.Messages {
+			texts = append(texts, message.GetContent())
+		}
+		got = append(texts, got...)
+		if page.NextCursor == "" {
+			break
+		}
+		request.Cursor = page.NextCursor
+	}
+	if len(got) != posts {
+		t.Fatalf("got %d post(s) in the history, want %d: %v", len(got), posts, got)
+	}
+	for n, text := range got {
+		if want := fmt.Sprintf("post %d", n); text != want {
+			t.Fatalf("got %q as post %d, want %q", text, n, want)
+		}
+	}
+}
+
+// The history lists every broadcast, not only those search can find: posts without words,
+// and events.
+func TestHistoryWordless(t *testing.T) {
+	s := newTestServer()
+	client := serveTest(t, s)
+	p, err := joinTest(t, client, "alice", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	posts := []string{"hello", "👍", "???", "world"}
+	for _, content := range posts {
+		_, err = p.post(client, content, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, err := client.GetHistory(context.Background(), &proto.HistoryRequest{Room: defaultRoom})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	joined := false
+	for _, message := range page.Messages {
+		if isChatText(message) {
+			texts = append(texts, message.GetContent())
+		}
+		if message.GetJoined().GetCallsign() == "alice" {
+			joined = true
+		}
+	}
+	if fmt.Sprint(texts) != fmt.Sprint(posts) {
+		t.Fatalf("got posts %q in the history, want %q", texts, posts)
+	}
+	if !joined {
+		t.Fatal("alice joining is not in the history")
+	}
+}
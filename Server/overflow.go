@@ -0,0 +1,107 @@
+package main
+
+import (
+	proto "example/chittychat/grpc"
+	"expvar"
+	"fmt"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Names of the slow-consumer policies accepted by newOverflowPolicy.
+const (
+	dropOldestPolicy = "drop-oldest"
+	dropNewestPolicy = "drop-newest"
+	disconnectPolicy = "disconnect"
+	spillPolicy      = "spill"
+)
+
+// How often each policy has been triggered, keyed by policy name.
+// Published under /debug/vars when the server is started with -metrics.
+var overflowCount = expvar.NewMap("overflow")
+
+// Decides what happens to a broadcast message when a client's feed is full.
+type overflowPolicy interface {
+	// Handles a message that did not fit in the feed of cli.
+	// The caller must hold s.mu.
+	handle(cli *client, message *proto.Message)
+}
+
+// Obtains the overflow policy for a policy name given on the command line.
+// The spill policy writes its queues to files in spillDir.
+func newOverflowPolicy(name string, spillDir string) (overflowPolicy, error) {
+	switch name {
+	case dropOldestPolicy:
+		return dropOldest{}, nil
+	case dropNewestPolicy:
+		return dropNewest{}, nil
+	case disconnectPolicy:
+		return disconnect{}, nil
+	case spillPolicy:
+		return spill{dir: spillDir}, nil
+	default:
+		return nil, fmt.Errorf("unknown overflow policy '%s' (want %s, %s, %s or %s)",
+			name, dropOldestPolicy, dropNewestPolicy, disconnectPolicy, spillPolicy)
+	}
+}
+
+// Counts and logs that a policy was triggered for a client.
+func countOverflow(policy string, cli *client) {
+	overflowCount.Add(policy, 1)
+	log.Printf("Warning: Feed overflow to %s, policy %s (triggered %s times)\n", cli.name, policy, overflowCount.Get(policy))
+}
+
+// Discards the oldest queued message to make room for the new one.
+// As with dropNewest, the client is later told how many messages it missed.
+type dropOldest struct{}
+
+func (dropOldest) handle(cli *client, message *proto.Message) {
+	countOverflow(dropOldestPolicy, cli)
+	select {
+	case <-cli.feed:
+		cli.dropped++
+	default: // The stream routine emptied a slot in the meantime.
+	}
+	select {
+	case cli.feed <- message:
+	default:
+	}
+}
+
+// Discards the new message. The client is told how many messages it missed
+// with a notice queued ahead of the next message that fits.
+type dropNewest struct{}
+
+func (dropNewest) handle(cli *client, message *proto.Message) {
+	countOverflow(dropNewestPolicy, cli)
+	cli.dropped++
+}
+
+// Disconnects the client, ending its stream with codes.ResourceExhausted.
+type disconnect struct{}
+
+func (disconnect) handle(cli *client, message *proto.Message) {
+	countOverflow(disconnectPolicy, cli)
+	select {
+	case cli.kick <- status.Error(codes.ResourceExhausted, "Disconnected for not keeping up with the chat."):
+	default: // Already being disconnected.
+	}
+}
+
+// Queues the message in a file for the client instead. The stream routine sends
+// spilled messages once the feed has been emptied, and until then new messages are
+// spilled as well, so the client still sees everything in order.
+type spill struct {
+	dir string
+}
+
+func (spill) handle(cli *client, message *proto.Message) {
+	countOverflow(spillPolicy, cli)
+	err := cli.spill.push(message)
+	if err != nil {
+		log.Printf("Client '%s': Spill failed, message dropped: %v\n", cli.name, err)
+		cli.dropped++
+	}
+}
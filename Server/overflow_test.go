@@ -0,0 +1,119 @@
+package main
+
+import (
+	proto "example/chittychat/grpc"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Obtains the contents of the messages queued in a client's feed, emptying it.
+func feedContents(cli *client) []string {
+	var contents []string
+	for len(cli.feed) > 0 {
+		message := <-cli.feed
+		if dropped := message.GetDropped(); dropped != nil {
+			contents = append(contents, fmt.Sprintf("dropped %d", dropped.Count))
+			continue
+		}
+		contents = append(contents, message.GetContent())
+	}
+	return contents
+}
+
+// Each policy, on a message that does not fit in a full feed.
+func TestOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		policy  string
+		feed    string // Contents of the feed after.
+		dropped int
+		spilled string
+		kicked  bool
+	}{
+		{dropOldestPolicy, "[b new]", 1, "[]", false},
+		{dropNewestPolicy, "[a b]", 1, "[]", false},
+		{disconnectPolicy, "[a b]", 0, "[]", true},
+		{spillPolicy, "[a b]", 0, "[new]", false},
+	}
+	for _, test := range tests {
+		t.Run(test.policy, func(t *testing.T) {
+			policy, err := newOverflowPolicy(test.policy, t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			cli := testClient(2, "a", "b")
+			if p, ok := policy.(spill); ok {
+				cli.spill = newSpillQueue(p.dir)
+				defer cli.spill.close()
+			}
+			policy.handle(cli, &proto.Message{Payload: &proto.Message_Content{Content: "new"}})
+
+			if got := fmt.Sprint(feedContents(cli)); got != test.feed {
+				t.Errorf("feed holds %v, want %v", got, test.feed)
+			}
+			if cli.dropped != test.dropped {
+				t.Errorf("%d message(s) dropped, want %d", cli.dropped, test.dropped)
+			}
+			spilled := "[]"
+			if cli.spill != nil {
+				spilled = fmt.Sprint(popTest(t, cli.spill))
+			}
+			if spilled != test.spilled {
+				t.Errorf("spilled %v, want %v", spilled, test.spilled)
+			}
+			var kick error
+			select {
+			case kick = <-cli.kick:
+			default:
+			}
+			if kicked := status.Code(kick) == codes.ResourceExhausted; kicked != test.kicked {
+				t.Errorf("got kick %v, want one: %v", kick, test.kicked)
+			}
+		})
+	}
+}
+
+func TestUnknownOverflowPolicy(t *testing.T) {
+	if _, err := newOverflowPolicy("shrug", ""); err == nil {
+		t.Fatal("got an unknown overflow policy")
+	}
+}
+
+// A client that had messages dropped is told how many ahead of the next message that fits.
+func TestQueueMessageDroppedNotice(t *testing.T) {
+	s := newTestServer()
+	cli := testClient(2, "a", "b")
+	cli.room = s.rooms[defaultRoom]
+	s.queueMessage(cli, &proto.Message{Payload: &proto.Message_Content{Content: "c"}})
+	<-cli.feed
+	<-cli.feed
+	s.queueMessage(cli, &proto.Message{Payload: &proto.Message_Content{Content: "d"}})
+	if got := fmt.Sprint(feedContents(cli)); got != "[dropped 1 d]" {
+		t.Fatalf("feed holds %v, want [dropped 1 d]", got)
+	}
+	if cli.dropped != 0 {
+		t.Fatalf("%d message(s) still counted as dropped after the notice", cli.dropped)
+	}
+}
+
+// Once messages are spilled, new ones are spilled behind them, even with room in the feed,
+// so the client gets them in order.
+func TestQueueMessageSpillsInOrder(t *testing.T) {
+	s := newTestServer()
+	s.overflow, _ = newOverflowPolicy(spillPolicy, t.TempDir())
+	cli := testClient(1, "a")
+	cli.room = s.rooms[defaultRoom]
+	cli.spill = newSpillQueue(s.overflow.(spill).dir)
+	defer cli.spill.close()
+	s.queueMessage(cli, &proto.Message{Payload: &proto.Message_Content{Content: "b"}})
+	<-cli.feed
+	s.queueMessage(cli, &proto.Message{Payload: &proto.Message_Content{Content: "c"}})
+	if len(cli.feed) != 0 {
+		t.Fatalf("%v queued in the feed ahead of spilled messages", feedContents(cli))
+	}
+	if got := fmt.Sprint(popTest(t, cli.spill)); got != "[b c]" {
+		t.Fatalf("spilled %v, want [b c]", got)
+	}
+}
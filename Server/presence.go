@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	proto "example/chittychat/grpc"
+	"log"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Number of presence events queued for a watcher. A watcher falling further behind is cut off.
+const presenceBuffer = 64
+
+// Describes a session's participant.
+// The caller must hold s.mu.
+func (sess *session) participant() *proto.Participant {
+	return &proto.Participant{
+		Callsign:      sess.callsign,
+		Room:          sess.room.name,
+		JoinedLamport: sess.joinedLamport,
+		Status:        sess.status,
+	}
+}
+
+// Lists the participants whose joining has been announced and whose session has not expired.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) participants() []*proto.Participant {
+	var list []*proto.Participant
+	for _, sess := range s.callsigns {
+		if sess.announced && !s.expired(sess) {
+			list = append(list, sess.participant())
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Callsign < list[j].Callsign
+	})
+	return list
+}
+
+// Lists the participants by callsign, with the room each is in and their status.
+// Participants whose stream broke are listed as RECONNECTING until their session expires.
+func (s *ChittyChatServer) ListParticipants(ctx context.Context, in *proto.Empty) (*proto.ParticipantList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &proto.ParticipantList{Participants: s.participants()}, nil
+}
+
+// The client obtains a stream of presence events. Method returns when the stream terminates.
+// The stream starts with a JOINED event for every participant already there, so that
+// together with the events that follow it describes everyone present.
+// A watcher falling behind by more than presenceBuffer events is cut off, and every
+// watcher is when the server shuts down.
+func (s *ChittyChatServer) WatchPresence(in *proto.Empty, stream grpc.ServerStreamingServer[proto.PresenceEvent]) error {
+	events := make(chan *proto.PresenceEvent, presenceBuffer)
+	s.mu.Lock()
+	if s.stopping {
+		s.mu.Unlock()
+		return status.Error(codes.Unavailable, "Server shutting down.")
+	}
+	initial := s.participants()
+	s.watchers[events] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.watchers, events)
+		s.mu.Unlock()
+	}()
+
+	for _, participant := range initial {
+		err := stream.Send(&proto.PresenceEvent{Kind: proto.PresenceEvent_JOINED, Participant: participant})
+		if err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				s.mu.Lock()
+				stopping := s.stopping
+				s.mu.Unlock()
+				if stopping {
+					return status.Error(codes.Unavailable, "Server shutting down.")
+				}
+				return status.Error(codes.ResourceExhausted, "Too many presence events queued.")
+			}
+			err := stream.Send(event)
+			if err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// Sets the caller's status. Only ONLINE and AWAY can be chosen; RECONNECTING is the server's.
+// The call must carry a session token.
+func (s *ChittyChatServer) SetStatus(ctx context.Context, in *proto.Participant) (*proto.Empty, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log.Printf("SetStatus: %v\n", in)
+
+	sess, err := s.sessionOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.Status != proto.Presence_ONLINE && in.Status != proto.Presence_AWAY {
+		return nil, status.Errorf(codes.InvalidArgument, "The status can only be set to %v or %v.", proto.Presence_ONLINE, proto.Presence_AWAY)
+	}
+	if sess.status != in.Status {
+		sess.status = in.Status
+		s.publishPresence(proto.PresenceEvent_UPDATED, sess)
+	}
+	return &proto.Empty{}, nil
+}
+
+// Sends a presence event about a session's participant to every watcher.
+// A watcher whose queue is full is cut off.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) publishPresence(kind proto.PresenceEvent_Kind, sess *session) {
+	event := &proto.PresenceEvent{Kind: kind, Participant: sess.participant()}
+	for events := range s.watchers {
+		select {
+		case events <- event:
+		default:
+			log.Printf("Presence watcher fell behind. Cutting it off.\n")
+			delete(s.watchers, events)
+			close(events)
+		}
+	}
+}
+
+// Releases a session once the grace period after its stream broke runs out, unless
+// the participant has joined again, or left, in the meantime. Watchers see it leave then.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) expireLater(sess *session) {
+	disconnected := sess.disconnected
+	time.AfterFunc(s.sessionGrace, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.callsigns[sess.callsign] == sess && sess.client == nil && sess.disconnected == disconnected {
+			log.Printf("Session of '%s': Expired.\n", sess.callsign)
+			s.releaseSession(sess)
+		}
+	})
+}
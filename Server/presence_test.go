@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	proto "example/chittychat/grpc"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Shutting down ends the streams of presence watchers, so the server can stop gracefully,
+// and refuses new watchers.
+func TestWatchPresenceShutdown(t *testing.T) {
+	s := newTestServer()
+	client, grpcServer := startTest(t, s)
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	stream, err := client.WatchPresence(ctx, &proto.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		s.mu.Lock()
+		watching := len(s.watchers)
+		s.mu.Unlock()
+		if watching == 1 {
+			break
+		}
+		if ctx.Err() != nil {
+			t.Fatal("the watcher was not registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stopped := make(chan bool)
+	go func() {
+		s.shutdown(grpcServer, testTimeout)
+		stopped <- true
+	}()
+	_, err = stream.Recv()
+	if status.Code(err) != codes.Unavailable || status.Convert(err).Message() != "Server shutting down." {
+		t.Fatalf("got %v, want the stream to end with the server shutting down", err)
+	}
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		t.Fatal("the server did not stop")
+	}
+
+	s.mu.Lock()
+	watching := len(s.watchers)
+	s.mu.Unlock()
+	if watching != 0 {
+		t.Fatalf("%d watcher(s) left after shutting down", watching)
+	}
+	err = s.WatchPresence(&proto.Empty{}, nil)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("got %v for a watcher after shutting down, want %v", err, codes.Unavailable)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"example/chittychat/clock"
+	proto "example/chittychat/grpc"
+	"log"
+	"sort"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Name of the room participants join unless they ask for another.
+const defaultRoom = "general"
+
+// Maximum number of characters in a room name.
+const maxRoomName = 32
+
+// A chat room. Each room is a message board of its own: it has its own participants,
+// announcements and broadcasts, and its own clock and sequence numbers, so that delivery
+// in one room never waits for messages only sent in another.
+// Rooms are guarded by s.mu.
+type room struct {
+	name     string
+	clients  []*client
+	clock    clock.Clock
+	sequence uint64
+	recent   *history
+}
+
+// Obtains a room with no participants.
+func (s *ChittyChatServer) newRoom(name string) *room {
+	clk, _ := clock.New(s.clockKind, "") // The kind was checked at startup.
+	return &room{
+		name:   name,
+		clock:  clk,
+		recent: newHistory(s.resumeBuffer),
+	}
+}
+
+// Obtains the room with a given name, the default room for "".
+// Returns a codes.NotFound status error if there is none.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) roomNamed(name string) (*room, error) {
+	if name == "" {
+		name = defaultRoom
+	}
+	r := s.rooms[name]
+	if r == nil {
+		return nil, status.Errorf(codes.NotFound, "There is no room '%s'.", name)
+	}
+	return r, nil
+}
+
+// Creates a room. The call must carry a session token.
+func (s *ChittyChatServer) CreateRoom(ctx context.Context, in *proto.Room) (*proto.Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log.Printf("CreateRoom: %v\n", in)
+
+	sess, err := s.sessionOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.Name == "" || utf8.RuneCountInString(in.Name) > maxRoomName {
+		return nil, status.Errorf(codes.InvalidArgument, "Room names have 1 to %d characters.", maxRoomName)
+	}
+	if s.rooms[in.Name] != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "The room '%s' already exists.", in.Name)
+	}
+	s.rooms[in.Name] = s.newRoom(in.Name)
+	if s.wal != nil {
+		err = s.wal.appendRoom(in.Name)
+		if err != nil {
+			log.Printf("Warning: Failed to log room '%s': %v\n", in.Name, err)
+		}
+	}
+	log.Printf("Room '%s': Created by %s.\n", in.Name, sess.callsign)
+	return &proto.Room{Name: in.Name}, nil
+}
+
+// Lists the rooms by name.
+func (s *ChittyChatServer) ListRooms(ctx context.Context, in *proto.Empty) (*proto.RoomList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := &proto.RoomList{}
+	for _, r := range s.rooms {
+		list.Rooms = append(list.Rooms, &proto.Room{
+			Name:         r.name,
+			Participants: int32(s.participantsIn(r)),
+		})
+	}
+	sort.Slice(list.Rooms, func(i, j int) bool {
+		return list.Rooms[i].Name < list.Rooms[j].Name
+	})
+	return list, nil
+}
+
+// Moves the caller's stream to another room. Leaving the old room and entering the new one
+// are announced in each, and the stream continues with the new room's welcome and a replay
+// of its last messages.
+// The confirm carries the caller's clock for the new room.
+// The call must carry a session token, and the session must have a stream.
+func (s *ChittyChatServer) JoinRoom(ctx context.Context, confirm *proto.Confirm) (*proto.Confirm, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log.Printf("JoinRoom: %v\n", confirm)
+
+	sess, err := s.sessionOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.roomNamed(confirm.Room)
+	if err != nil {
+		return nil, err
+	}
+	cli := sess.client
+	if cli == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Not connected. Join the message board first.")
+	}
+	target.setTime(confirm.Timestamp())
+	if cli.room != target {
+		old := cli.room
+		s.removeFromRoom(cli)
+		s.leftChatMessage(old, cli.name, "")
+
+		cli.room = target
+		sess.room = target
+		welcome := s.welcomeMessage(target, cli.name)
+		s.queueWelcome(cli, welcome[0])
+		for _, message := range s.replayedMessages(target) {
+			s.queueMessage(cli, message)
+		}
+		target.clients = append(target.clients, cli)
+		s.enteredRoom(sess, s.enteredChatMessage(target, cli.name))
+	}
+
+	reply := &proto.Confirm{
+		Author: s.name,
+		Room:   target.name,
+	}
+	reply.SetTimestamp(target.getTime())
+	return reply, nil
+}
+
+// Queues the welcome to a room in the feed of a client moving there.
+// The client starts over on the welcome, so it must not be dropped: it goes behind any
+// spilled messages, and if the feed is full, messages still queued from the old room
+// make way for it. If there is still no room for it, the client is disconnected, and
+// starts over by joining again.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) queueWelcome(cli *client, welcome *proto.Message) {
+	if len(cli.isClosed) > 0 {
+		return
+	}
+	if cli.spill != nil && cli.spill.len() > 0 {
+		err := cli.spill.push(welcome)
+		if err == nil {
+			return
+		}
+		log.Printf("Client '%s': Spill failed: %v\n", cli.name, err)
+	}
+	for attempt := 0; attempt <= cap(cli.feed); attempt++ {
+		select {
+		case cli.feed <- welcome:
+			return
+		default:
+		}
+		select {
+		case <-cli.feed:
+		default:
+		}
+	}
+	log.Printf("Client '%s': No room for the welcome to %s. Disconnecting.\n", cli.name, welcome.Room)
+	select {
+	case cli.kick <- status.Error(codes.ResourceExhausted, "Could not queue the welcome to the room."):
+	default: // Already being disconnected.
+	}
+}
+
+// Counts the participants connected to a room.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) participantsIn(r *room) int {
+	count := 0
+	for _, cli := range r.clients {
+		if len(cli.isClosed) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Gets the next timestamp of the room.
+// The caller must hold s.mu.
+func (r *room) getTime() clock.Timestamp {
+	return r.clock.Tick()
+}
+
+// Updates the room's clock to reflect an incoming timestamp.
+// The caller must hold s.mu.
+func (r *room) setTime(in clock.Timestamp) {
+	r.clock.Witness(in)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	proto "example/chittychat/grpc"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Obtains a client with a feed of a size, holding the messages given.
+func testClient(size int, queued ...string) *client {
+	cli := &client{
+		name:     "alice",
+		feed:     make(chan *proto.Message, size),
+		isClosed: make(chan bool, 1),
+		kick:     make(chan error, 1),
+	}
+	for _, content := range queued {
+		cli.feed <- &proto.Message{Payload: &proto.Message_Content{Content: content}}
+	}
+	return cli
+}
+
+func TestQueueWelcome(t *testing.T) {
+	s := newTestServer()
+	welcome := &proto.Message{Payload: &proto.Message_Welcome{Welcome: &proto.WelcomeEvent{Callsign: "alice"}}, Room: "lobby"}
+	tests := []struct {
+		name   string
+		cli    *client
+		closed bool
+		queued int // Messages in the feed after, the welcome last if it was queued.
+		kicked bool
+	}{
+		{"room in the feed", testClient(3, "a"), false, 2, false},
+		{"full feed", testClient(3, "a", "b", "c"), false, 3, false},
+		{"full feed of size 1", testClient(1, "a"), false, 1, false},
+		{"closed", testClient(3, "a"), true, 1, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.closed {
+				test.cli.isClosed <- true
+			}
+			s.queueWelcome(test.cli, welcome)
+			if len(test.cli.feed) != test.queued {
+				t.Fatalf("%d message(s) in the feed, want %d", len(test.cli.feed), test.queued)
+			}
+			var last *proto.Message
+			for len(test.cli.feed) > 0 {
+				last = <-test.cli.feed
+			}
+			if queued := last == welcome; queued != (test.queued > 0 && !test.closed) {
+				t.Fatalf("welcome queued last: %v", queued)
+			}
+			var kick error
+			select {
+			case kick = <-test.cli.kick:
+			default:
+			}
+			if kicked := status.Code(kick) == codes.ResourceExhausted; kicked != test.kicked {
+				t.Fatalf("got kick %v, want one: %v", kick, test.kicked)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	proto "example/chittychat/grpc"
+	"sort"
+	"strings"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Limits of SearchMessages.
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchContext   = 5
+)
+
+// An inverted index over the content of the messages broadcast, in any room.
+// It keeps the last limit broadcasts, events included, as the history of each room. Only
+// their words are indexed, so events and other messages without words are never found,
+// but they are shown around the messages that are. Documents are numbered in the order
+// they were broadcast, and the oldest is forgotten when the index is full.
+// It is guarded by s.mu.
+type searchIndex struct {
+	docs     []*proto.Message     // By document number, less base.
+	base     int                  // Document number of docs[0].
+	limit    int                  // Nothing is indexed if 0.
+	postings map[string][]posting // By word, ordered by document number.
+	rooms    map[string][]int     // Document numbers of the messages in each room, in order.
+}
+
+// The occurrences of a word in a message.
+type posting struct {
+	doc       int
+	positions []int // Word positions in the content.
+}
+
+// Obtains an empty index keeping at most limit messages.
+func newSearchIndex(limit int) *searchIndex {
+	return &searchIndex{
+		limit:    limit,
+		postings: make(map[string][]posting),
+		rooms:    make(map[string][]int),
+	}
+}
+
+// Splits text into lower-case words at anything that is not a letter or digit.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Adds a broadcast message to the index, forgetting the oldest if the index is full.
+// A message without words gets no postings.
+func (x *searchIndex) add(message *proto.Message) {
+	if x.limit <= 0 {
+		return
+	}
+	positions := make(map[string][]int)
+	for i, word := range words(message.GetContent()) {
+		positions[word] = append(positions[word], i)
+	}
+	if len(x.docs) == x.limit {
+		x.evict()
+	}
+
+	doc := x.base + len(x.docs)
+	x.docs = append(x.docs, message)
+	x.rooms[message.Room] = append(x.rooms[message.Room], doc)
+	for word, at := range positions {
+		x.postings[word] = append(x.postings[word], posting{doc: doc, positions: at})
+	}
+}
+
+// Forgets the oldest document. Being the oldest, it is first in each list it is on.
+func (x *searchIndex) evict() {
+	oldest := x.docs[0]
+	x.docs[0] = nil
+	x.docs = x.docs[1:]
+	x.base++
+
+	for _, word := range words(oldest.GetContent()) {
+		list := x.postings[word]
+		if len(list) == 0 || list[0].doc != x.base-1 {
+			continue // A word occurring more than once has been seen to.
+		}
+		if len(list) == 1 {
+			delete(x.postings, word)
+		} else {
+			x.postings[word] = list[1:]
+		}
+	}
+	if room := x.rooms[oldest.Room]; len(room) == 1 {
+		delete(x.rooms, oldest.Room)
+	} else {
+		x.rooms[oldest.Room] = room[1:]
+	}
+}
+
+// Obtains the message of a document.
+func (x *searchIndex) message(doc int) *proto.Message {
+	return x.docs[doc-x.base]
+}
+
+// Obtains the positions of a word in a document, or nil if it does not occur there.
+func (x *searchIndex) positions(word string, doc int) []int {
+	list := x.postings[word]
+	i := sort.Search(len(list), func(i int) bool { return list[i].doc >= doc })
+	if i < len(list) && list[i].doc == doc {
+		return list[i].positions
+	}
+	return nil
+}
+
+// Reports whether a document contains a phrase: its words at consecutive positions.
+func (x *searchIndex) containsPhrase(phrase []string, doc int) bool {
+	for _, start := range x.positions(phrase[0], doc) {
+		found := true
+		for i, word := range phrase[1:] {
+			if !containsInt(x.positions(word, doc), start+i+1) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether a sorted list contains a number.
+func containsInt(list []int, n int) bool {
+	i := sort.SearchInts(list, n)
+	return i < len(list) && list[i] == n
+}
+
+// Splits a query into phrases: each quoted phrase, and each word outside quotes as a phrase of one.
+// A quote left open runs to the end of the query.
+func parseQuery(query string) [][]string {
+	var phrases [][]string
+	for i, part := range strings.Split(query, "\"") {
+		if i%2 == 1 {
+			if phrase := words(part); len(phrase) > 0 {
+				phrases = append(phrases, phrase)
+			}
+			continue
+		}
+		for _, word := range words(part) {
+			phrases = append(phrases, []string{word})
+		}
+	}
+	return phrases
+}
+
+// Finds the documents containing every phrase, newest first, up to limit.
+// Candidates are the documents containing the rarest word of the query.
+func (x *searchIndex) search(phrases [][]string, limit int, accept func(*proto.Message) bool) []int {
+	rarest := x.postings[phrases[0][0]]
+	for _, phrase := range phrases {
+		for _, word := range phrase {
+			if list := x.postings[word]; len(list) < len(rarest) {
+				rarest = list
+			}
+		}
+	}
+
+	var found []int
+	for i := len(rarest) - 1; i >= 0 && len(found) < limit; i-- {
+		doc := rarest[i].doc
+		if !accept(x.message(doc)) {
+			continue
+		}
+		matches := true
+		for _, phrase := range phrases {
+			if !x.containsPhrase(phrase, doc) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			found = append(found, doc)
+		}
+	}
+	return found
+}
+
+// Obtains a search result for a document, with up to around messages of its room on each side.
+func (x *searchIndex) result(doc int, around int) *proto.SearchResult {
+	message := x.message(doc)
+	room := x.rooms[message.Room]
+	at := sort.SearchInts(room, doc)
+	result := &proto.SearchResult{Message: message}
+	for _, other := range room[max(0, at-around):at] {
+		result.Before = append(result.Before, x.message(other))
+	}
+	for _, other := range room[at+1 : min(len(room), at+1+around)] {
+		result.After = append(result.After, x.message(other))
+	}
+	return result
+}
+
+// Searches the messages broadcast in every room, or the one given.
+func (s *ChittyChatServer) SearchMessages(ctx context.Context, in *proto.SearchRequest) (*proto.SearchResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	phrases := parseQuery(in.Query)
+	if len(phrases) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "The query has no words.")
+	}
+	if in.Room != "" {
+		_, err := s.roomNamed(in.Room)
+		if err != nil {
+			return nil, err
+		}
+	}
+	limit := int(in.Limit)
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+	around := min(max(int(in.Context), 0), maxSearchContext)
+
+	accept := func(message *proto.Message) bool {
+		return (in.Room == "" || message.Room == in.Room) &&
+			(in.Author == "" || message.Author == in.Author) &&
+			(in.MinLamport == 0 || message.LamportTs >= in.MinLamport) &&
+			(in.MaxLamport == 0 || message.LamportTs <= in.MaxLamport)
+	}
+	response := &proto.SearchResponse{}
+	for _, doc := range s.index.search(phrases, limit, accept) {
+		response.Results = append(response.Results, s.index.result(doc, around))
+	}
+	return response, nil
+}
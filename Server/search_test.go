@@ -0,0 +1,87 @@
+package main
+
+import (
+	proto "example/chittychat/grpc"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// Obtains chat text broadcast in a room.
+func broadcast(room string, content string) *proto.Message {
+	return &proto.Message{Payload: &proto.Message_Content{Content: content}, Author: "alice", Room: room}
+}
+
+// Searches an index for a query in any room, returning the contents found, newest first.
+func searchTest(x *searchIndex, query string) []string {
+	var found []string
+	for _, doc := range x.search(parseQuery(query), maxSearchLimit, func(*proto.Message) bool { return true }) {
+		found = append(found, x.message(doc).GetContent())
+	}
+	return found
+}
+
+// Messages without words are kept, for the history, but get no postings.
+func TestSearchIndexWordless(t *testing.T) {
+	x := newSearchIndex(10)
+	x.add(&proto.Message{Payload: &proto.Message_Joined{Joined: &proto.JoinEvent{Callsign: "alice"}}, Room: "general"})
+	x.add(broadcast("general", "!!!"))
+	x.add(broadcast("general", "hello there"))
+	if len(x.docs) != 3 || len(x.rooms["general"]) != 3 {
+		t.Fatalf("kept %d message(s), want all 3", len(x.docs))
+	}
+	if len(x.postings) != 2 {
+		t.Fatalf("got postings of %d word(s), want 2", len(x.postings))
+	}
+}
+
+// Once the index is full, the oldest message is forgotten: it is no longer found, nor shown
+// around the messages that remain, and the words only it had are gone.
+func TestSearchIndexEviction(t *testing.T) {
+	x := newSearchIndex(3)
+	x.add(broadcast("general", "alpha alpha shared"))
+	x.add(broadcast("ops", "beta shared"))
+	x.add(broadcast("general", "gamma shared"))
+	x.add(broadcast("general", "delta shared"))
+	x.add(broadcast("ops", "epsilon"))
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"alpha", nil},
+		{"beta", nil},
+		{"shared", []string{"delta shared", "gamma shared"}},
+		{"epsilon", []string{"epsilon"}},
+	}
+	for _, test := range tests {
+		if got := searchTest(x, test.query); !slices.Equal(got, test.want) {
+			t.Errorf("search %q: got %v, want %v", test.query, got, test.want)
+		}
+	}
+	for _, word := range []string{"alpha", "beta"} {
+		if _, ok := x.postings[word]; ok {
+			t.Errorf("postings of %q kept after its messages were forgotten", word)
+		}
+	}
+	if len(x.postings["shared"]) != 2 {
+		t.Errorf("%d posting(s) of \"shared\", want 2", len(x.postings["shared"]))
+	}
+	if fmt.Sprint(x.rooms["general"], x.rooms["ops"]) != "[2 3] [4]" {
+		t.Errorf("rooms hold %v and %v, want [2 3] and [4]", x.rooms["general"], x.rooms["ops"])
+	}
+
+	delta := x.search(parseQuery("delta"), 1, func(*proto.Message) bool { return true })[0]
+	result := x.result(delta, 5)
+	if len(result.Before) != 1 || result.Before[0].GetContent() != "gamma shared" || len(result.After) != 0 {
+		t.Errorf("got %v before and %v after delta, want only gamma before", result.Before, result.After)
+	}
+}
+
+func TestSearchIndexOff(t *testing.T) {
+	x := newSearchIndex(0)
+	x.add(broadcast("general", "hello"))
+	if len(x.docs) != 0 || len(searchTest(x, "hello")) != 0 {
+		t.Fatal("an index of size 0 kept a message")
+	}
+}
@@ -2,202 +2,725 @@ package main
 
 import (
 	"context"
+	"example/chittychat/clock"
+	"example/chittychat/config"
 	proto "example/chittychat/grpc"
+	"flag"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"sync"
+	"syscall"
+	"time"
 	"unicode/utf8"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	pb "google.golang.org/protobuf/proto"
 )
 
+// Flags not given on the command line are taken from environment variables with this
+// prefix, e.g. CHITTYCHAT_SERVER_ADDRESS, and then from the -config file.
+const envPrefix = "CHITTYCHAT_SERVER_"
+
+var address = flag.String("address", "localhost:5050", "network address to listen on")
+var logFile = flag.String("log-file", "server.txt", "file to write the log to")
+var serverName = flag.String("name", "ChittyServer", "author of the server's announcements")
+var maxLength = flag.Int("max-length", 128, "maximum number of characters in a message")
+var feedSize = flag.Int("feed-size", 20, "number of messages queued for a client before the overflow policy applies")
+var configFile = flag.String(config.FileFlag, "", "JSON config file with flag values, keyed by flag name")
+var printConfig = flag.Bool("print-config", false, "print the effective configuration as JSON and exit")
+var clockKind = flag.String("clock", clock.VectorKind, "logical clock: lamport, vector or hybrid")
+var overflowName = flag.String("overflow", dropNewestPolicy, "what to do when a client falls behind: drop-oldest, drop-newest, disconnect or spill")
+var spillDir = flag.String("spill-dir", os.TempDir(), "directory for the queues of the spill overflow policy")
+var resumeBuffer = flag.Int("resume-buffer", 256, "number of recent messages kept for clients resuming after a reconnect")
+var indexSize = flag.Int("index-size", 100000, "number of broadcasts kept for search and history, across all rooms")
+var dedupWindowSize = flag.Int("dedup-window", 256, "number of message ids remembered per participant, so that posts sent again are not broadcast twice")
+var historyDepth = flag.Int("history-depth", 10, "number of earlier messages replayed to a participant joining a room (at most -resume-buffer)")
+var walPath = flag.String("wal", "", "file to log broadcasts to, and restore them from on startup (off if empty)")
+var walSync = flag.String("wal-sync", syncInterval, "when to sync the -wal file to disk: always, interval or never")
+var walSyncInterval = flag.Duration("wal-sync-interval", time.Second, "time between syncs of the -wal file with -wal-sync interval")
+var sessionGrace = flag.Duration("session-grace", time.Minute, "time a callsign stays reserved for its participant after the stream breaks")
+var drainTimeout = flag.Duration("drain-timeout", 5*time.Second, "time to let clients receive queued messages when shutting down")
+var tlsCert = flag.String("tls-cert", "", "certificate file for serving over TLS (plaintext if empty)")
+var tlsKey = flag.String("tls-key", "", "key file for -tls-cert")
+var tlsClientCA = flag.String("tls-client-ca", "", "CA file for verifying client certificates; enables mutual TLS, where the certificate's common name is the callsign")
+var authMode = flag.String("auth", noAuth, "authentication of calls: none, secret (one password shared by all) or file (per-user passwords)")
+var authSecret = flag.String("auth-secret", "", "password shared by all participants for -auth secret")
+var authFile = flag.String("auth-file", "", "credential file for -auth file, with a username:bcrypt-hash line per user; the username is the callsign")
+var hashPassword = flag.String("hash-password", "", "print a credential file line for the given username, with the password read from standard input, and exit")
+var metricsAddr = flag.String("metrics", "", "address to serve metrics on at /debug/vars, e.g. localhost:5051 (off if empty)")
+
 // Represents a running ChittyChat server.
+// The mutex guards the rooms, with their client lists and clocks. RPC handlers run
+// concurrently, so every clock tick, registration and fan-out happens under it.
+//
+// The server relays messages rather than taking part in the conversation, so
+// a vector clock owns no entry for it. It only merges the vectors it receives,
+// and stamps its own announcements with everything it has seen.
 type ChittyChatServer struct {
 	proto.UnimplementedChittyChatServiceServer
-	clients     []client
-	name        string
-	lamportTime int64
+	mu           sync.Mutex
+	rooms        map[string]*room // By name.
+	name         string
+	clockKind    string // Of the clock of each room.
+	maxLength    int    // In characters.
+	feedSize     int
+	resumeBuffer int // Number of recent messages kept in each room.
+	historyDepth int // Number of recent messages replayed on joining a room.
+	overflow     overflowPolicy
+	auth         authenticator // Nil if calls are not authenticated.
+	wal          *messageLog   // Nil if broadcasts are not logged.
+	index        *searchIndex
+	dedup        *dedupWindow
+
+	sessions     map[string]*session // By token.
+	callsigns    map[string]*session // By callsign.
+	sessionGrace time.Duration
+	watchers     map[chan *proto.PresenceEvent]bool // Of WatchPresence streams.
+	stopping     bool                               // Set once the streams are ended for shutdown.
 }
 
 // Channels for the connection to a client.
 // Each connection is a running coroutine.
 type client struct {
 	name     string
+	room     *room // Guarded by s.mu.
 	feed     chan *proto.Message
 	isClosed chan bool
+	kick     chan error  // Ends the connection with the given error, or normally if nil.
+	left     bool        // Left through LeaveMessageBoard. Guarded by s.mu.
+	dropped  int         // Messages dropped since the client was last told. Guarded by s.mu.
+	spill    *spillQueue // Only used by the spill overflow policy.
 }
 
-// The client obtains a stream of the chat. Method returns when the stream terminates.
+// The client obtains a stream of the chat in a room. Method returns when the stream terminates.
+// The callsign is reserved for the client, and the token of its session is sent in the
+// response header. A client joining again with the token of its session takes it over.
+// Under mutual TLS, the callsign is the common name of the client's certificate,
+// and with per-user passwords it is the authenticated username.
+// A client resuming after a broken stream is first sent the messages it missed,
+// and a client joining afresh is sent the room's last messages as a replay.
 func (s *ChittyChatServer) JoinMessageBoard(confirm *proto.Confirm, stream grpc.ServerStreamingServer[proto.Message]) error {
-	s.setTime(confirm.LamportTs)
+	return s.joinMessageBoard(confirm, stream, nil)
+}
+
+// Joins a client to the message board and streams the chat to it, for JoinMessageBoard and Chat.
+// Once the client has been welcomed and its joining announced, joined is called with its
+// session, unless it is nil. Method returns when the stream terminates.
+func (s *ChittyChatServer) joinMessageBoard(confirm *proto.Confirm, stream grpc.ServerStreamingServer[proto.Message], joined func(*session)) error {
+	s.mu.Lock()
 	log.Printf("JoinMessageBoard: %v\n", confirm)
+	r, err := s.roomNamed(confirm.Room)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	r.setTime(confirm.Timestamp())
+	if callsign, ok := certificateName(stream.Context()); ok {
+		confirm.Author = callsign
+	}
+	if callsign, ok := authenticatedName(stream.Context()); ok {
+		confirm.Author = callsign
+	}
+	sess, err := s.reserveCallsign(confirm.Author, tokenOf(stream.Context()))
+	if err != nil {
+		s.mu.Unlock()
+		log.Printf("JoinMessageBoard: %v\n", err)
+		return err
+	}
+	if sess.client != nil {
+		s.replaceClient(sess.client)
+	}
+	// The welcome and the registration happen together, so the welcome's timestamps
+	// cover exactly the broadcasts this client will not see. Later ones queue in its feed.
+	welcome := s.welcomeMessage(r, confirm.Author)
+	if confirm.ResumeAfter > 0 && confirm.ResumeAfter <= r.sequence {
+		welcome = append(welcome, s.missedMessages(r, confirm.ResumeAfter, welcome[0])...)
+	} else {
+		welcome = append(welcome, s.replayedMessages(r)...)
+	}
+	cli := s.addNewClient(r, confirm)
+	sess.client = cli
+	sess.room = r
+	s.mu.Unlock()
 
-	err := s.welcomeClient(stream, confirm.Author)
+	err = stream.SendHeader(metadata.Pairs(sessionTokenKey, sess.token))
+	if err == nil {
+		err = s.welcomeClient(stream, welcome)
+	}
 	if err != nil {
+		// The client never got its welcome, so its joining is not announced, but the session
+		// is held for the grace period like that of any broken stream, so it can join again.
+		s.mu.Lock()
+		cli.isClosed <- true
+		if cli.spill != nil {
+			cli.spill.close()
+		}
+		s.removeFromRoom(cli)
+		if sess.client == cli {
+			sess.client = nil
+			sess.disconnected = time.Now()
+			if sess.announced && sess.status != proto.Presence_RECONNECTING {
+				sess.status = proto.Presence_RECONNECTING
+				s.publishPresence(proto.PresenceEvent_UPDATED, sess)
+			}
+			s.expireLater(sess)
+		}
+		s.mu.Unlock()
 		return err
 	}
 
-	cli := s.addNewClient(confirm)
-	s.enteredChatMessage(cli.name)
+	s.mu.Lock()
+	lamport := s.enteredChatMessage(cli.room, cli.name)
+	if sess.client == cli {
+		s.enteredRoom(sess, lamport)
+	}
+	s.mu.Unlock()
+	if joined != nil {
+		joined(sess)
+	}
+
+	err = cli.streamToClientRoutine(stream) // Continues until connection terminates.
 
-	cli.streamToClientRoutine(stream) // Continues until connection terminates.
+	s.mu.Lock()
+	if sess.client == cli {
+		sess.client = nil
+		sess.disconnected = time.Now()
+		if sess.announced && !cli.left {
+			sess.status = proto.Presence_RECONNECTING
+			s.publishPresence(proto.PresenceEvent_UPDATED, sess)
+		}
+		s.expireLater(sess)
+	}
+	if !cli.left && err != nil {
+		s.kickedChatMessage(cli.room, cli.name, status.Convert(err).Message())
+	} else if !cli.left {
+		s.leftChatMessage(cli.room, cli.name, "")
+	}
+	s.mu.Unlock()
 
-	s.leftChatMessage(cli.name)
+	return err
+}
 
-	return nil
+// The client leaves the chat. The leave is announced at once, stamped after the client's
+// own clock, and the client's stream is ended.
+func (s *ChittyChatServer) LeaveMessageBoard(ctx context.Context, in *proto.Message) (*proto.Confirm, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log.Printf("LeaveMessageBoard: %v\n", in)
+
+	sess, err := s.sessionOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sess.room.setTime(in.Timestamp())
+	s.releaseSession(sess)
+	if sess.client != nil {
+		s.endClient(sess.client, nil)
+	}
+
+	s.leftChatMessage(sess.room, sess.callsign, in.GetContent())
+	return &proto.Confirm{
+		Author:    s.name,
+		LamportTs: sess.room.getTime().Lamport,
+		Room:      sess.room.name,
+	}, nil
 }
 
-// The incoming message is broadcasted; queued in the feed of all clients.
+// The incoming message is broadcasted; queued in the feed of all clients in the sender's room.
 // The server returns a confirm message with a timestamp.
+// The call must carry a session token, and the message is posted under its callsign.
+// A message naming a room must name the sender's room.
 func (s *ChittyChatServer) PostMessage(ctx context.Context, in *proto.Message) (*proto.Confirm, error) {
-	s.setTime(in.LamportTs)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, err := s.sessionOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.post(sess, in)
+}
 
-	if utf8.RuneCountInString(in.Content) > 128 {
-		log.Printf("PostMessage: Invalid input, Content too long! From '" + in.Author + "' at Lamport time " + strconv.FormatInt(s.getTime(), 10))
+// Broadcasts a message under a session's callsign, for PostMessage and Chat.
+// A post whose message id is in the author's dedup window was already broadcast,
+// and gets the original confirm instead, even if the author has moved on to another room.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) post(sess *session, in *proto.Message) (*proto.Confirm, error) {
+	err := checkMessageID(in.MessageId)
+	if err != nil {
+		return nil, err
+	}
+	if confirm := s.dedup.lookup(sess.callsign, in.MessageId); confirm != nil {
+		log.Printf("PostMessage: '%s' sent message %s again. Confirming it again.\n", sess.callsign, in.MessageId)
+		return confirm, nil
+	}
+	r := sess.room
+	if in.Room != "" && in.Room != r.name {
+		return nil, status.Errorf(codes.FailedPrecondition, "You are not in room '%s'.", in.Room)
+	}
+	in.Author = sess.callsign
+	if !isChatText(in) {
+		return nil, status.Error(codes.InvalidArgument, "Only chat text can be posted.")
+	}
+	if utf8.RuneCountInString(in.GetContent()) > s.maxLength {
+		log.Printf("PostMessage: Invalid input, Content too long! From '" + in.Author + "' at Lamport time " + strconv.FormatInt(in.LamportTs, 10))
 		return nil, status.Error(codes.Aborted, "Content too long!")
 	}
+	// Only a post that is broadcast is witnessed. The room's clock would otherwise take in
+	// a vector entry nobody receives, and hold up causal delivery of everything after it.
+	r.setTime(in.Timestamp())
 
 	log.Printf("PostMessage: %v\n", in)
-	s.broadcastMessage(in)
-	return &proto.Confirm{
+	s.broadcastMessage(r, in)
+	confirm := &proto.Confirm{
 		Author:    s.name,
-		LamportTs: s.getTime(),
-	}, nil
+		LamportTs: r.getTime().Lamport,
+		VectorTs:  in.VectorTs,
+		Room:      r.name,
+	}
+	s.dedup.remember(sess.callsign, in.MessageId, confirm)
+	return confirm, nil
 }
 
-// Generates a public join message for broadcast.
-func (s *ChittyChatServer) enteredChatMessage(name string) {
-	time := s.getTime()
-	s.broadcastMessage(&proto.Message{
-		Content:   "Participant " + name + " joined Chitty-Chat at Lamport time " + strconv.FormatInt(time, 10),
-		Author:    s.name,
-		LamportTs: time,
-	})
+// Reports whether a message is chat text, as participants post, rather than an event.
+// A message without a payload is empty chat text.
+func isChatText(message *proto.Message) bool {
+	switch message.Payload.(type) {
+	case *proto.Message_Content, nil:
+		return true
+	}
+	return false
 }
 
-// Generates a public leave message for broadcast.
-func (s *ChittyChatServer) leftChatMessage(name string) {
-	time := s.getTime()
-	s.broadcastMessage(&proto.Message{
-		Content:   "Participant " + name + " left Chitty-Chat at Lamport time " + strconv.FormatInt(time, 10),
-		Author:    s.name,
-		LamportTs: time,
-	})
+// Generates a public join event for broadcast. Returns its Lamport time.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) enteredChatMessage(r *room, name string) int64 {
+	message := &proto.Message{
+		Payload: &proto.Message_Joined{Joined: &proto.JoinEvent{Callsign: name}},
+		Author:  s.name,
+	}
+	message.SetTimestamp(r.getTime())
+	s.broadcastMessage(r, message)
+	return message.LamportTs
 }
 
-// Sends an initial message to client and returns nil.
-// If an error occurs, it is logged, and a status error for the RPC is returned.
-func (s *ChittyChatServer) welcomeClient(stream grpc.ServerStreamingServer[proto.Message], name string) error {
-	msg := proto.Message{
-		Content:   "\U0001F680 Welcome to ChittyChat, " + name + "! \U0001F680",
-		Author:    s.name,
-		LamportTs: s.getTime(),
+// Records that a session's participant entered its room at a Lamport time, and tells
+// the presence watchers: that it joined, or that it is back or moved if already listed.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) enteredRoom(sess *session, lamport int64) {
+	sess.joinedLamport = lamport
+	if sess.status == proto.Presence_RECONNECTING {
+		sess.status = proto.Presence_ONLINE
 	}
-	err := stream.Send(&msg)
-	if err != nil {
-		log.Printf("Handshake error: %v\n", err)
-		return status.Error(codes.Aborted, err.Error())
-	} else {
-		return nil
+	if sess.announced {
+		s.publishPresence(proto.PresenceEvent_UPDATED, sess)
+		return
+	}
+	sess.announced = true
+	s.publishPresence(proto.PresenceEvent_JOINED, sess)
+}
+
+// Generates a public leave event for broadcast, with the farewell if one was given.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) leftChatMessage(r *room, name string, farewell string) {
+	message := &proto.Message{
+		Payload: &proto.Message_Left{Left: &proto.LeaveEvent{Callsign: name, Farewell: farewell}},
+		Author:  s.name,
+	}
+	message.SetTimestamp(r.getTime())
+	s.broadcastMessage(r, message)
+}
+
+// Generates a public kick event for broadcast, for a participant the server disconnected.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) kickedChatMessage(r *room, name string, reason string) {
+	message := &proto.Message{
+		Payload: &proto.Message_Kicked{Kicked: &proto.KickEvent{Callsign: name, Reason: reason}},
+		Author:  s.name,
 	}
+	message.SetTimestamp(r.getTime())
+	s.broadcastMessage(r, message)
 }
 
-// Add a new channel struct for control and feed from server to active client stream.
-func (s *ChittyChatServer) addNewClient(confirm *proto.Confirm) client {
-	cli := client{
+// Generates the private welcome event for a client joining a room.
+// It is returned as the first of the initial messages for welcomeClient to send.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) welcomeMessage(r *room, name string) []*proto.Message {
+	message := &proto.Message{
+		Payload:  &proto.Message_Welcome{Welcome: &proto.WelcomeEvent{Callsign: name}},
+		Author:   s.name,
+		Sequence: r.sequence,
+		Room:     r.name,
+	}
+	message.SetTimestamp(r.getTime())
+	return []*proto.Message{message}
+}
+
+// Obtains the messages broadcast in a room after a resume cursor, from its buffer of recent messages.
+// If some have already left the buffer, the welcome says how many.
+// The welcome's sequence number is moved back to the cursor, so the client takes
+// the missed messages as coming next.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) missedMessages(r *room, resumeAfter uint64, welcome *proto.Message) []*proto.Message {
+	// Every broadcast has a sequence number and goes into the buffer, so those after the
+	// cursor that are not there anymore are lost. The buffer may well be empty.
+	missed := r.recent.since(resumeAfter)
+	lost := r.sequence - resumeAfter - uint64(len(missed))
+	welcome.Sequence = resumeAfter + lost
+	welcome.GetWelcome().Unavailable = lost
+	return missed
+}
+
+// Obtains copies of the last messages broadcast in a room, up to the history depth,
+// marked as replayed. They are sent after the welcome, with their original timestamps
+// and sequence numbers.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) replayedMessages(r *room) []*proto.Message {
+	var replayed []*proto.Message
+	for _, message := range r.recent.last(s.historyDepth) {
+		message = pb.Clone(message).(*proto.Message)
+		message.Replayed = true
+		replayed = append(replayed, message)
+	}
+	return replayed
+}
+
+// Sends the initial messages to client and returns nil.
+// If an error occurs, it is logged, and a status error for the RPC is returned.
+// The stream is written without holding s.mu, so a slow client cannot stall the server.
+func (s *ChittyChatServer) welcomeClient(stream grpc.ServerStreamingServer[proto.Message], messages []*proto.Message) error {
+	for _, msg := range messages {
+		err := stream.Send(msg)
+		if err != nil {
+			log.Printf("Handshake error: %v\n", err)
+			return status.Error(codes.Aborted, err.Error())
+		}
+	}
+	return nil
+}
+
+// Add a new channel struct for control and feed from server to active client stream,
+// as a member of a room.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) addNewClient(r *room, confirm *proto.Confirm) *client {
+	cli := &client{
 		name:     confirm.Author,
-		feed:     make(chan *proto.Message, 20),
+		room:     r,
+		feed:     make(chan *proto.Message, s.feedSize),
 		isClosed: make(chan bool, 1),
+		kick:     make(chan error, 1),
+	}
+	if policy, ok := s.overflow.(spill); ok {
+		cli.spill = newSpillQueue(policy.dir)
 	}
-	s.clients = append(s.clients, cli)
+	r.clients = append(r.clients, cli)
 	return cli
 }
 
 // Routine call that handles the stream to a client.
 // Runs for the duration of each client connection.
-func (cli *client) streamToClientRoutine(stream grpc.ServerStreamingServer[proto.Message]) {
+// Returns the error the client was disconnected with, if it was.
+func (cli *client) streamToClientRoutine(stream grpc.ServerStreamingServer[proto.Message]) error {
 	done := stream.Context().Done()
+	var spilled chan struct{} // Stays nil unless the spill policy is in use.
+	if cli.spill != nil {
+		spilled = cli.spill.signal
+		defer cli.spill.close()
+	}
+	var kicked error
 
 main:
 	for {
+		var err error
 		select {
 		case message := <-cli.feed:
-			err := stream.Send(message)
-			if err != nil {
-				log.Printf("Client '%s': Stream error. Closing...\n", cli.name)
-				break main
+			err = stream.Send(message)
+			if err == nil && len(cli.feed) == 0 {
+				err = cli.sendSpilled(stream)
+			}
+		case <-spilled:
+			if len(cli.feed) == 0 {
+				err = cli.sendSpilled(stream)
+			}
+		case kicked = <-cli.kick:
+			if kicked == nil {
+				log.Printf("Client '%s': Left. Closing...\n", cli.name)
+			} else {
+				log.Printf("Client '%s': Disconnected: %v\n", cli.name, kicked)
 			}
+			break main
 		case <-done:
 			log.Printf("Client '%s': Stream terminated. Closing...\n", cli.name)
 			break main
 		}
+		if err != nil {
+			log.Printf("Client '%s': Stream error. Closing...\n", cli.name)
+			break main
+		}
 	}
 
 	cli.isClosed <- true
+	return kicked
 }
 
-// Adds a message to the feed channel of each client connection.
+// Sends the messages in the spill queue, oldest first, until it is empty.
+// Only call this when the feed is empty, as spilled messages come after those in the feed.
+func (cli *client) sendSpilled(stream grpc.ServerStreamingServer[proto.Message]) error {
+	if cli.spill == nil {
+		return nil
+	}
+	for {
+		message, err := cli.spill.pop()
+		if message == nil || err != nil {
+			return err
+		}
+		err = stream.Send(message)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Adds a message to the feed channel of each client connection in a room.
 // Closed connections are pruned as messages are sent.
 // The message is sent with the next Lamport timestamp, to reflect
 // that repeating the message comes after receiving and processing.
-func (s *ChittyChatServer) broadcastMessage(message *proto.Message) {
-	message.LamportTs = s.getTime()
-	for i := 0; i < len(s.clients); i++ {
-		cli := s.clients[i]
+//
+// Each broadcast also gets the room's next sequence number. As both are assigned under s.mu,
+// sequence order is (Lamport time, author) order, and every feed is filled in that order.
+// Clients in total-order mode use the sequence to detect and wait out gaps,
+// and resuming clients use it to ask for what they missed from r.recent.
+// The message is also indexed for search, and appended to the message log, if there is one.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) broadcastMessage(r *room, message *proto.Message) {
+	message.LamportTs = r.getTime().Lamport
+	r.sequence++
+	message.Sequence = r.sequence
+	message.Room = r.name
+	r.recent.add(message)
+	s.index.add(message)
+	if s.wal != nil {
+		err := s.wal.appendMessage(message)
+		if err != nil {
+			log.Printf("Warning: Failed to log message %d in %s: %v\n", message.Sequence, r.name, err)
+		}
+	}
+	for i := 0; i < len(r.clients); i++ {
+		cli := r.clients[i]
 		select {
 		case <-cli.isClosed:
-			s.removeClient(i)
+			s.removeClient(r, i)
 			i--
-		case cli.feed <- message:
 		default:
-			log.Printf("Warning: Feed overflow to %s\n", cli.name)
+			s.queueMessage(cli, message)
 		}
 	}
 }
 
+// Queues a message in the feed of a client, leaving it to the overflow policy if it does not fit.
+// A client that has had messages dropped is first sent an event saying how many.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) queueMessage(cli *client, message *proto.Message) {
+	if cli.dropped > 0 {
+		notice := &proto.Message{
+			Payload:   &proto.Message_Dropped{Dropped: &proto.DroppedEvent{Count: int32(cli.dropped)}},
+			Author:    s.name,
+			LamportTs: cli.room.getTime().Lamport,
+			Room:      cli.room.name,
+		}
+		select {
+		case cli.feed <- notice:
+			cli.dropped = 0
+		default:
+		}
+	}
+
+	if cli.spill != nil && cli.spill.len() > 0 {
+		s.overflow.handle(cli, message) // Stay in order behind the spilled messages.
+		return
+	}
+	select {
+	case cli.feed <- message:
+	default:
+		s.overflow.handle(cli, message)
+	}
+}
+
+// Ends the stream to a client with the given error, or normally if nil,
+// and removes it from the connections without announcing that it left.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) endClient(cli *client, err error) {
+	cli.left = true
+	select {
+	case cli.kick <- err:
+	default: // Already being disconnected.
+	}
+	s.removeFromRoom(cli)
+}
+
+// Ends the stream to a client whose participant has joined again on a new stream.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) replaceClient(cli *client) {
+	log.Printf("Client '%s': Replaced by a new stream.\n", cli.name)
+	s.endClient(cli, status.Error(codes.Aborted, "Replaced by a newer connection."))
+}
+
 // Dereferences a clients slices from the channel.
 // Only do this when communication to the client has been terminated.
-func (s *ChittyChatServer) removeClient(i int) {
-	cli := s.clients[i]
-	s.clients = append(s.clients[:i], s.clients[i+1:]...)
-	log.Printf("Client '%s': Removed from connections.\n", cli.name)
+// The caller must hold s.mu.
+func (s *ChittyChatServer) removeClient(r *room, i int) {
+	cli := r.clients[i]
+	r.clients = append(r.clients[:i], r.clients[i+1:]...)
+	log.Printf("Client '%s': Removed from connections in %s.\n", cli.name, r.name)
 }
 
-// Gets the next Lamport timestamp.
-func (s *ChittyChatServer) getTime() int64 {
-	s.lamportTime++
-	return s.lamportTime
+// Removes a client from the connections of its room, e.g. when it moves to another.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) removeFromRoom(cli *client) {
+	for i := range cli.room.clients {
+		if cli.room.clients[i] == cli {
+			s.removeClient(cli.room, i)
+			break
+		}
+	}
 }
 
-// Updates the Lamport timestamp to reflect an incoming timestamp.
-func (s *ChittyChatServer) setTime(in int64) {
-	if s.lamportTime < in {
-		s.lamportTime = in
+// Checks that the sizes and times set by flags, from the command line or configuration, make sense.
+func checkFlags() error {
+	switch {
+	case *maxLength < 1:
+		return fmt.Errorf("-max-length must be at least 1, not %d", *maxLength)
+	case *feedSize < 1:
+		return fmt.Errorf("-feed-size must be at least 1, not %d", *feedSize)
+	case *resumeBuffer < 0:
+		return fmt.Errorf("-resume-buffer must not be negative, not %d", *resumeBuffer)
+	case *historyDepth < 0 || *historyDepth > *resumeBuffer:
+		return fmt.Errorf("-history-depth must be between 0 and -resume-buffer (%d), not %d", *resumeBuffer, *historyDepth)
+	case *dedupWindowSize < 0:
+		return fmt.Errorf("-dedup-window must not be negative, not %d", *dedupWindowSize)
+	case *sessionGrace < 0:
+		return fmt.Errorf("-session-grace must not be negative, not %v", *sessionGrace)
+	case *drainTimeout < 0:
+		return fmt.Errorf("-drain-timeout must not be negative, not %v", *drainTimeout)
+	case *walSyncInterval <= 0:
+		return fmt.Errorf("-wal-sync-interval must be positive, not %v", *walSyncInterval)
+	case *indexSize < 1:
+		return fmt.Errorf("-index-size must be at least 1, not %d", *indexSize)
 	}
+	return nil
 }
 
 // Start point for program.
 func main() {
-	logfile, err := os.Create("server.txt")
+	flag.Parse()
+	err := config.Load(flag.CommandLine, envPrefix)
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v\n", err)
+	}
+	if *printConfig {
+		config.Print(os.Stdout, flag.CommandLine, []string{"print-config", "hash-password"}, []string{"auth-secret"})
+		return
+	}
+	if *hashPassword != "" {
+		printCredentialLine(*hashPassword)
+		return
+	}
+	_, err = clock.New(*clockKind, "") // Fail early on an unknown kind.
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	err = checkFlags()
+	if err != nil {
+		log.Fatalf("invalid configuration: %v\n", err)
+	}
+
+	logfile, err := os.Create(*logFile)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
 	log.SetOutput(logfile)
 
+	overflow, err := newOverflowPolicy(*overflowName, *spillDir)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	auth, err := newAuthenticator(*authMode, *authSecret, *authFile)
+	if err != nil {
+		log.Fatalf("failed to set up authentication: %v\n", err)
+	}
+	if *metricsAddr != "" {
+		go serveMetrics(*metricsAddr)
+	}
+
 	server := ChittyChatServer{
-		clients: make([]client, 0),
-		name:    "ChittyServer",
+		rooms:        make(map[string]*room),
+		index:        newSearchIndex(*indexSize),
+		dedup:        newDedupWindow(*dedupWindowSize),
+		name:         *serverName,
+		clockKind:    *clockKind,
+		maxLength:    *maxLength,
+		feedSize:     *feedSize,
+		resumeBuffer: *resumeBuffer,
+		historyDepth: *historyDepth,
+		overflow:     overflow,
+		auth:         auth,
+
+		sessions:     make(map[string]*session),
+		callsigns:    make(map[string]*session),
+		sessionGrace: *sessionGrace,
+		watchers:     make(map[chan *proto.PresenceEvent]bool),
+	}
+	server.rooms[defaultRoom] = server.newRoom(defaultRoom)
+	if *walPath != "" {
+		server.wal, err = openMessageLog(*walPath, *walSync, *walSyncInterval)
+		if err != nil {
+			log.Fatalf("failed to open message log: %v\n", err)
+		}
+		err = server.restore()
+		if err != nil {
+			log.Fatalf("failed to restore from message log: %v\n", err)
+		}
 	}
 
-	listener := listenOn("localhost:5050")
-	server.startService(listener)
+	var options []grpc.ServerOption
+	if *tlsCert != "" {
+		option, err := tlsServerOption(*tlsCert, *tlsKey, *tlsClientCA)
+		if err != nil {
+			log.Fatalf("failed to set up TLS: %v\n", err)
+		}
+		options = append(options, option)
+	}
+	if server.auth != nil {
+		options = append(options,
+			grpc.ChainUnaryInterceptor(server.unaryAuthInterceptor),
+			grpc.ChainStreamInterceptor(server.streamAuthInterceptor))
+	}
+
+	listener := listenOn(*address)
+	grpcServer := server.startService(listener, options...)
+
+	// Run until terminated manually or by error.
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	<-stop.Done()
 
-	wait := make(chan bool)
-	<-wait // Run until terminated manually or by error.
+	server.shutdown(grpcServer, *drainTimeout)
+	if server.wal != nil {
+		err = server.wal.close()
+		if err != nil {
+			log.Printf("failed to close message log: %v\n", err)
+		}
+	}
+	logfile.Sync()
+	logfile.Close()
 }
 
 // Obtains a TCP listener on a given network address.
@@ -209,14 +732,99 @@ func listenOn(address string) net.Listener {
 	return listener
 }
 
+// Serves the expvar metrics at /debug/vars on a given network address.
+func serveMetrics(address string) {
+	log.Printf("metrics at http://%s/debug/vars\n", address)
+	err := http.ListenAndServe(address, nil)
+	if err != nil {
+		log.Printf("failed to serve metrics: %v\n", err)
+	}
+}
+
 // Begins serving ChittyChat gRPC service as a goroutine and returns.
-func (s *ChittyChatServer) startService(listener net.Listener) {
-	grpcServer := grpc.NewServer()
+func (s *ChittyChatServer) startService(listener net.Listener, options ...grpc.ServerOption) *grpc.Server {
+	grpcServer := grpc.NewServer(options...)
 	proto.RegisterChittyChatServiceServer(grpcServer, s)
 	fmt.Printf("server listening at %v\n", listener.Addr())
 	log.Printf("server listening at %v\n", listener.Addr())
-	err := grpcServer.Serve(listener)
-	if err != nil {
-		log.Fatalf("failed to serve: %v\n", err)
+	go func() {
+		err := grpcServer.Serve(listener)
+		if err != nil {
+			log.Fatalf("failed to serve: %v\n", err)
+		}
+	}()
+	return grpcServer
+}
+
+// Stops the server, letting clients know first.
+// A shutdown notice is broadcast, and clients get up to drainTimeout to receive
+// what is queued for them. Their streams are then ended, along with those of presence
+// watchers, and the gRPC server stopped.
+// Clients still connected when the drain timeout runs out a second time are cut off.
+func (s *ChittyChatServer) shutdown(grpcServer *grpc.Server, drainTimeout time.Duration) {
+	fmt.Println("server shutting down")
+	log.Printf("Shutting down. Draining feeds for up to %v.\n", drainTimeout)
+
+	s.mu.Lock()
+	for _, r := range s.rooms {
+		notice := &proto.Message{
+			Payload: &proto.Message_Shutdown{Shutdown: &proto.ShutdownEvent{DrainTimeoutMs: drainTimeout.Milliseconds()}},
+			Author:  s.name,
+		}
+		notice.SetTimestamp(r.getTime())
+		s.broadcastMessage(r, notice)
+	}
+	s.mu.Unlock()
+
+	deadline := time.Now().Add(drainTimeout)
+	for !s.feedsDrained() && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	s.mu.Lock()
+	s.stopping = true
+	for _, r := range s.rooms {
+		for _, cli := range r.clients {
+			select {
+			case cli.kick <- status.Error(codes.Unavailable, "Server shutting down."):
+			default:
+			}
+		}
+	}
+	for events := range s.watchers {
+		delete(s.watchers, events)
+		close(events)
+	}
+	s.mu.Unlock()
+
+	stopped := make(chan bool)
+	go func() {
+		grpcServer.GracefulStop()
+		stopped <- true
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(drainTimeout):
+		log.Printf("Graceful stop timed out. Stopping.\n")
+		grpcServer.Stop()
+	}
+	log.Printf("Overflow policy triggered: %v\n", overflowCount)
+	log.Printf("Server stopped.\n")
+}
+
+// Reports whether every connected client has been sent everything queued for it.
+func (s *ChittyChatServer) feedsDrained() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.rooms {
+		for _, cli := range r.clients {
+			if len(cli.isClosed) > 0 {
+				continue // Nobody left to send to.
+			}
+			if len(cli.feed) > 0 || (cli.spill != nil && cli.spill.len() > 0) {
+				return false
+			}
+		}
 	}
+	return true
 }
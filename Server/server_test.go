@@ -0,0 +1,572 @@
+package main
+
+import (
+	"context"
+	"example/chittychat/clock"
+	proto "example/chittychat/grpc"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	pb "google.golang.org/protobuf/proto"
+)
+
+// Time a test waits for the messages it expects.
+const testTimeout = 10 * time.Second
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// Obtains a server with the default settings, with feeds large enough that nothing is dropped.
+func newTestServer() *ChittyChatServer {
+	overflow, _ := newOverflowPolicy(dropNewestPolicy, "")
+	s := &ChittyChatServer{
+		rooms:        make(map[string]*room),
+		index:        newSearchIndex(100000),
+		dedup:        newDedupWindow(256),
+		name:         "ChittyServer",
+		clockKind:    clock.VectorKind,
+		maxLength:    128,
+		feedSize:     10000,
+		resumeBuffer: 256,
+		historyDepth: 10,
+		overflow:     overflow,
+
+		sessions:     make(map[string]*session),
+		callsigns:    make(map[string]*session),
+		sessionGrace: time.Minute,
+		watchers:     make(map[chan *proto.PresenceEvent]bool),
+	}
+	s.rooms[defaultRoom] = s.newRoom(defaultRoom)
+	return s
+}
+
+// Serves a server on an in-memory listener until the test ends, and returns a client of it.
+func serveTest(t *testing.T, s *ChittyChatServer) proto.ChittyChatServiceClient {
+	t.Helper()
+	client, _ := startTest(t, s)
+	return client
+}
+
+// Serves a server on an in-memory listener until the test ends, and returns a client of it
+// and the gRPC server, for a test to stop.
+func startTest(t *testing.T, s *ChittyChatServer) (proto.ChittyChatServiceClient, *grpc.Server) {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := s.startService(listener)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		grpcServer.Stop()
+	})
+	return proto.NewChittyChatServiceClient(conn), grpcServer
+}
+
+// A participant in a test: its stream, the welcome it got, its session token and a context carrying it.
+type testParticipant struct {
+	name    string
+	stream  grpc.ServerStreamingClient[proto.Message]
+	welcome *proto.Message
+	token   string
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// Joins the message board as a participant, presenting a session token if it is not empty.
+// The stream ends when the test does.
+func joinTest(t *testing.T, client proto.ChittyChatServiceClient, name string, token string, resumeAfter uint64) (*testParticipant, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, sessionTokenKey, token)
+	}
+	stream, err := client.JoinMessageBoard(ctx, &proto.Confirm{Author: name, ResumeAfter: resumeAfter})
+	if err != nil {
+		return nil, err
+	}
+	header, err := stream.Header()
+	if err != nil {
+		return nil, err
+	}
+	welcome, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	tokens := header.Get(sessionTokenKey)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no session token for %s", name)
+	}
+	return &testParticipant{
+		name:    name,
+		stream:  stream,
+		welcome: welcome,
+		token:   tokens[0],
+		ctx:     metadata.AppendToOutgoingContext(context.Background(), sessionTokenKey, tokens[0]),
+		cancel:  cancel,
+	}, nil
+}
+
+// Posts chat text as a participant.
+func (p *testParticipant) post(client proto.ChittyChatServiceClient, content string, id string) (*proto.Confirm, error) {
+	message := &proto.Message{
+		Payload:   &proto.Message_Content{Content: content},
+		Author:    p.name,
+		MessageId: id,
+	}
+	return client.PostMessage(p.ctx, message)
+}
+
+// Receives the broadcasts after the participant's welcome up to and including a sequence
+// number, skipping replayed messages and anything without a sequence number.
+func (p *testParticipant) receiveUntil(last uint64) ([]*proto.Message, error) {
+	var received []*proto.Message
+	timeout := time.AfterFunc(testTimeout, p.cancel)
+	defer timeout.Stop()
+	for seen := p.welcome.Sequence; seen < last; {
+		message, err := p.stream.Recv()
+		if err != nil {
+			return received, fmt.Errorf("%s: %v after %d message(s)", p.name, err, len(received))
+		}
+		if message.Replayed || message.Sequence == 0 {
+			continue
+		}
+		received = append(received, message)
+		seen = message.Sequence
+	}
+	return received, nil
+}
+
+// Obtains the last sequence number assigned in a room.
+func lastSequence(s *ChittyChatServer, name string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rooms[name].sequence
+}
+
+// Checks that a participant got the consecutive sequence numbers following its welcome.
+func checkConsecutive(p *testParticipant, received []*proto.Message) error {
+	next := p.welcome.Sequence + 1
+	for _, message := range received {
+		if message.Sequence != next {
+			return fmt.Errorf("%s got sequence %d, want %d", p.name, message.Sequence, next)
+		}
+		next++
+	}
+	return nil
+}
+
+// Many participants join and post at once, some joining while others post. Every participant
+// must get every broadcast from its welcome on, once and in sequence, and the participants
+// there from the start must get every post. Run with -race.
+func TestConcurrentPostersAndJoiners(t *testing.T) {
+	const posters = 8
+	const latecomers = 8
+	const posts = 25
+	s := newTestServer()
+	client := serveTest(t, s)
+
+	participants := make([]*testParticipant, posters+latecomers)
+	var wg sync.WaitGroup
+	errs := make(chan error, posters*posts+latecomers+posters)
+	for i := 0; i < posters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p, err := joinTest(t, client, fmt.Sprintf("poster%d", i), "", 0)
+			if err != nil {
+				errs <- err
+				return
+			}
+			participants[i] = p
+		}(i)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		t.Fatal(<-errs)
+	}
+
+	for i := 0; i < posters; i++ {
+		wg.Add(1)
+		go func(p *testParticipant) {
+			defer wg.Done()
+			for n := 0; n < posts; n++ {
+				_, err := p.post(client, fmt.Sprintf("%s-%d", p.name, n), "")
+				if err != nil {
+					errs <- err
+				}
+			}
+		}(participants[i])
+	}
+	for i := posters; i < posters+latecomers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p, err := joinTest(t, client, fmt.Sprintf("latecomer%d", i), "", 0)
+			if err != nil {
+				errs <- err
+				return
+			}
+			participants[i] = p
+		}(i)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		t.Fatal(<-errs)
+	}
+
+	last := lastSequence(s, defaultRoom)
+	for i, p := range participants {
+		wg.Add(1)
+		go func(i int, p *testParticipant) {
+			defer wg.Done()
+			received, err := p.receiveUntil(last)
+			if err != nil {
+				errs <- err
+				return
+			}
+			err = checkConsecutive(p, received)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if i >= posters {
+				return
+			}
+			seen := make(map[string]bool)
+			for _, message := range received {
+				if _, ok := message.Payload.(*proto.Message_Content); ok {
+					seen[message.GetContent()] = true
+				}
+			}
+			for _, poster := range participants[:posters] {
+				for n := 0; n < posts; n++ {
+					if content := fmt.Sprintf("%s-%d", poster.name, n); !seen[content] {
+						errs <- fmt.Errorf("%s did not get %q", p.name, content)
+						return
+					}
+				}
+			}
+		}(i, p)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		t.Fatal(<-errs)
+	}
+}
+
+// Participants in -delivery total show broadcasts in sequence order, so for them all to show
+// the same transcript, every feed must carry the same broadcast under each sequence number,
+// without gaps. Several participants post at once, and all they receive after the last of
+// them joined must be identical.
+func TestTotalOrderTranscripts(t *testing.T) {
+	const participants = 5
+	const posts = 40
+	s := newTestServer()
+	client := serveTest(t, s)
+
+	joined := make([]*testParticipant, participants)
+	for i := range joined {
+		p, err := joinTest(t, client, fmt.Sprintf("p%d", i), "", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		joined[i] = p
+	}
+	start := joined[participants-1].welcome.Sequence
+
+	var wg sync.WaitGroup
+	errs := make(chan error, participants*posts)
+	for _, p := range joined {
+		wg.Add(1)
+		go func(p *testParticipant) {
+			defer wg.Done()
+			for n := 0; n < posts; n++ {
+				_, err := p.post(client, fmt.Sprintf("%s-%d", p.name, n), "")
+				if err != nil {
+					errs <- err
+				}
+			}
+		}(p)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		t.Fatal(<-errs)
+	}
+
+	last := lastSequence(s, defaultRoom)
+	var first []*proto.Message
+	for _, p := range joined {
+		received, err := p.receiveUntil(last)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = checkConsecutive(p, received)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var transcript []*proto.Message
+		for _, message := range received {
+			if message.Sequence > start {
+				transcript = append(transcript, message)
+			}
+		}
+		if first == nil {
+			first = transcript
+			continue
+		}
+		if len(transcript) != len(first) {
+			t.Fatalf("%s got %d message(s), %s got %d", p.name, len(transcript), joined[0].name, len(first))
+		}
+		for i := range transcript {
+			if !pb.Equal(transcript[i], first[i]) {
+				t.Fatalf("%s got %v as message %d, %s got %v", p.name, transcript[i], i, joined[0].name, first[i])
+			}
+		}
+	}
+	texts := 0
+	for _, message := range first {
+		if _, ok := message.Payload.(*proto.Message_Content); ok {
+			texts++
+		}
+	}
+	if texts != participants*posts {
+		t.Fatalf("got %d post(s), want %d", texts, participants*posts)
+	}
+}
+
+// A refused post must not reach the room's clock: announcements stamped with a vector entry
+// nobody received would be held back by every participant in causal mode.
+func TestRefusedPostNotWitnessed(t *testing.T) {
+	s := newTestServer()
+	client := serveTest(t, s)
+	p, err := joinTest(t, client, "alice", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	long := &proto.Message{
+		Payload:   &proto.Message_Content{Content: strings.Repeat("x", s.maxLength+1)},
+		LamportTs: 100,
+		VectorTs:  map[string]int64{"alice": 1},
+	}
+	_, err = client.PostMessage(p.ctx, long)
+	if status.Code(err) != codes.Aborted {
+		t.Fatalf("got %v for a post that is too long, want %v", err, codes.Aborted)
+	}
+	s.mu.Lock()
+	now := s.rooms[defaultRoom].clock.Now()
+	s.mu.Unlock()
+	if now.Vector["alice"] != 0 || now.Lamport >= 100 {
+		t.Fatalf("room clock at %v after a refused post", now)
+	}
+}
+
+// A stream that breaks before the participant is welcomed.
+type brokenStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (b brokenStream) Context() context.Context     { return b.ctx }
+func (b brokenStream) SendHeader(metadata.MD) error { return status.Error(codes.Unavailable, "broken") }
+func (b brokenStream) Send(*proto.Message) error    { return status.Error(codes.Unavailable, "broken") }
+
+// A participant whose stream breaks before the welcome is not left among the room's
+// connections, and its session expires after the grace period.
+func TestJoinSendFails(t *testing.T) {
+	s := newTestServer()
+	s.sessionGrace = 50 * time.Millisecond
+	err := s.joinMessageBoard(&proto.Confirm{Author: "alice"}, brokenStream{ctx: context.Background()}, nil)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("got %v, want %v", err, codes.Unavailable)
+	}
+	s.mu.Lock()
+	clients := len(s.rooms[defaultRoom].clients)
+	sess := s.callsigns["alice"]
+	held := sess != nil && sess.client == nil
+	s.mu.Unlock()
+	if clients != 0 {
+		t.Fatalf("%d client(s) left in the room", clients)
+	}
+	if !held {
+		t.Fatal("the session is not held without a client")
+	}
+
+	deadline := time.Now().Add(testTimeout)
+	for {
+		s.mu.Lock()
+		released := s.callsigns["alice"] == nil
+		s.mu.Unlock()
+		if released {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the session did not expire")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Joins, posts and leaves on a Chat stream: the post is acknowledged on the stream, and
+// the stream ends normally once the participant has left.
+func TestChat(t *testing.T) {
+	s := newTestServer()
+	client := serveTest(t, s)
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	stream, err := client.Chat(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stream.Send(&proto.ChatRequest{Id: 1, Kind: &proto.ChatRequest_Join{Join: &proto.Confirm{Author: "alice"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := stream.Header()
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := &proto.Message{Payload: &proto.Message_Content{Content: "hello"}, MessageId: "m1"}
+	err = stream.Send(&proto.ChatRequest{Id: 2, Kind: &proto.ChatRequest_Post{Post: post}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ack *proto.ChatAck
+	posted := false
+	for ack == nil || !posted {
+		response, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response.GetAck() != nil {
+			ack = response.GetAck()
+		}
+		if response.GetMessage().GetContent() == "hello" {
+			posted = true
+		}
+	}
+	if ack.Id != 2 || ack.Error != "" || ack.Confirm == nil {
+		t.Fatalf("got acknowledgement %v, want a confirm of post 2", ack)
+	}
+
+	leave := metadata.AppendToOutgoingContext(ctx, sessionTokenKey, header.Get(sessionTokenKey)[0])
+	_, err = client.LeaveMessageBoard(leave, &proto.Message{Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		_, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("got %v after leaving, want the stream to end normally", err)
+		}
+	}
+}
+
+func TestCheckFlags(t *testing.T) {
+	tests := []struct {
+		flags map[string]string
+		ok    bool
+	}{
+		{map[string]string{}, true},
+		{map[string]string{"max-length": "0"}, false},
+		{map[string]string{"feed-size": "1"}, true},
+		{map[string]string{"feed-size": "0"}, false},
+		{map[string]string{"resume-buffer": "-1", "history-depth": "0"}, false},
+		{map[string]string{"resume-buffer": "0", "history-depth": "0"}, true},
+		{map[string]string{"resume-buffer": "5", "history-depth": "6"}, false},
+		{map[string]string{"history-depth": "-1"}, false},
+		{map[string]string{"dedup-window": "-1"}, false},
+		{map[string]string{"index-size": "0"}, false},
+		{map[string]string{"wal-sync-interval": "0s"}, false},
+		{map[string]string{"wal-sync-interval": "-1s"}, false},
+		{map[string]string{"session-grace": "0s"}, true},
+		{map[string]string{"session-grace": "-1s"}, false},
+		{map[string]string{"drain-timeout": "-1s"}, false},
+	}
+	for _, test := range tests {
+		for name, value := range test.flags {
+			err := flag.Set(name, value)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		err := checkFlags()
+		if (err == nil) != test.ok {
+			t.Errorf("flags %v: got %v, want ok: %v", test.flags, err, test.ok)
+		}
+		for name := range test.flags {
+			flag.Set(name, flag.Lookup(name).DefValue)
+		}
+	}
+}
+
+// A participant resuming after messages left the buffer, or with no buffer at all, is told
+// how many it lost, and takes the messages still there as coming next.
+func TestResumeLost(t *testing.T) {
+	for _, buffer := range []int{0, 5, 256} {
+		t.Run(fmt.Sprint("buffer ", buffer), func(t *testing.T) {
+			s := newTestServer()
+			s.resumeBuffer = buffer
+			s.historyDepth = 0
+			s.rooms[defaultRoom] = s.newRoom(defaultRoom)
+			client := serveTest(t, s)
+			p, err := joinTest(t, client, "alice", "", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for n := 0; n < 10; n++ {
+				_, err = p.post(client, fmt.Sprint("post ", n), "")
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			last := lastSequence(s, defaultRoom)
+
+			resumed, err := joinTest(t, client, "alice", p.token, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			kept := min(uint64(buffer), last-1)
+			lost := last - 1 - kept
+			welcome := resumed.welcome
+			if welcome.GetWelcome().Unavailable != lost || welcome.Sequence != 1+lost {
+				t.Fatalf("got welcome at %d with %d unavailable, want %d with %d", welcome.Sequence, welcome.GetWelcome().Unavailable, 1+lost, lost)
+			}
+			for sequence := 1 + lost + 1; sequence <= last; sequence++ {
+				message, err := resumed.stream.Recv()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if message.Sequence != sequence {
+					t.Fatalf("got sequence %d after the welcome, want %d", message.Sequence, sequence)
+				}
+			}
+		})
+	}
+}
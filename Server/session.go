@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	proto "example/chittychat/grpc"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata key of the session token. JoinMessageBoard sends it in the response header,
+// and clients send it back on every later call.
+const sessionTokenKey = "session-token"
+
+// Maximum number of characters in a callsign.
+const maxCallsign = 32
+
+// A participant's claim on a callsign. It outlives a broken stream for a grace period,
+// so that the participant can join again under the same callsign by presenting the token.
+type session struct {
+	callsign     string
+	token        string
+	room         *room     // The room the participant is in.
+	client       *client   // The connected stream, or nil if there is none.
+	disconnected time.Time // When client was last set to nil.
+
+	announced     bool           // Its joining has been announced, so it is listed as a participant.
+	joinedLamport int64          // Lamport time of joining its room, on the room's clock.
+	status        proto.Presence // RECONNECTING while there is no stream.
+}
+
+// Reserves a callsign for a joining client and returns its session.
+// A client presenting the token of the callsign's session takes that session over;
+// otherwise the callsign must be free, or its session must have expired.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) reserveCallsign(callsign string, token string) (*session, error) {
+	err := checkCallsign(callsign)
+	if err != nil {
+		return nil, err
+	}
+	sess := s.callsigns[callsign]
+	if sess != nil && sess.token != token && !s.expired(sess) {
+		return nil, status.Errorf(codes.AlreadyExists, "The callsign '%s' is taken.", callsign)
+	}
+	if sess != nil && sess.token == token {
+		return sess, nil
+	}
+	if sess != nil {
+		s.releaseSession(sess)
+	}
+
+	sess = &session{
+		callsign: callsign,
+		token:    newToken(),
+	}
+	s.callsigns[callsign] = sess
+	s.sessions[sess.token] = sess
+	return sess, nil
+}
+
+// Checks a callsign. Returns a codes.InvalidArgument status error if it is empty, too long,
+// or has spaces or control characters in it: others could not address it with /msg.
+func checkCallsign(callsign string) error {
+	if callsign == "" || utf8.RuneCountInString(callsign) > maxCallsign {
+		return status.Errorf(codes.InvalidArgument, "Callsigns have 1 to %d characters.", maxCallsign)
+	}
+	if strings.IndexFunc(callsign, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		return status.Error(codes.InvalidArgument, "Callsigns cannot contain spaces.")
+	}
+	return nil
+}
+
+// Reports whether a session without a stream has outlived the grace period.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) expired(sess *session) bool {
+	return sess.client == nil && time.Since(sess.disconnected) > s.sessionGrace
+}
+
+// Obtains the session whose token the call carries in its metadata.
+// Returns a codes.Unauthenticated status error if there is none.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) sessionOf(ctx context.Context) (*session, error) {
+	sess := s.sessions[tokenOf(ctx)]
+	if sess == nil || s.expired(sess) {
+		return nil, status.Error(codes.Unauthenticated, "No session. Join the message board first.")
+	}
+	return sess, nil
+}
+
+// Frees a session's callsign. Presence watchers see its participant leave.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) releaseSession(sess *session) {
+	delete(s.callsigns, sess.callsign)
+	delete(s.sessions, sess.token)
+	if sess.announced {
+		s.publishPresence(proto.PresenceEvent_LEFT, sess)
+	}
+}
+
+// Reads the session token from the metadata of an incoming call. Returns "" if there is none.
+func tokenOf(ctx context.Context) string {
+	values := metadata.ValueFromIncomingContext(ctx, sessionTokenKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Generates a random session token.
+func newToken() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
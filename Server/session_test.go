@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckCallsign(t *testing.T) {
+	tests := []struct {
+		callsign string
+		ok       bool
+	}{
+		{"alice", true},
+		{"Ålice_2", true},
+		{strings.Repeat("a", maxCallsign), true},
+		{strings.Repeat("a", maxCallsign+1), false},
+		{"", false},
+		{"alice smith", false},
+		{"alice\t", false},
+		{" alice", false},
+		{"ali\x00ce", false},
+	}
+	for _, test := range tests {
+		err := checkCallsign(test.callsign)
+		if (err == nil) != test.ok {
+			t.Errorf("%q: got %v, want ok: %v", test.callsign, err, test.ok)
+		}
+	}
+}
+
+// A callsign others could not address is refused on joining, and not reserved.
+func TestJoinInvalidCallsign(t *testing.T) {
+	s := newTestServer()
+	client := serveTest(t, s)
+	_, err := joinTest(t, client, "alice smith", "", 0)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("got %v joining as 'alice smith', want %v", err, codes.InvalidArgument)
+	}
+	s.mu.Lock()
+	reserved := len(s.callsigns)
+	s.mu.Unlock()
+	if reserved != 0 {
+		t.Fatalf("%d callsign(s) reserved", reserved)
+	}
+}
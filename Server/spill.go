@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	proto "example/chittychat/grpc"
+	"io"
+	"os"
+	"sync"
+
+	pb "google.golang.org/protobuf/proto"
+)
+
+// A per-client queue of messages on disk, for clients that cannot keep up.
+// Messages are appended to a file as length-prefixed protobuf records and read
+// back in order. The file is created on the first push and truncated whenever
+// the queue runs empty. Once closed, the queue takes no more messages, so no file
+// is created that nobody would remove.
+type spillQueue struct {
+	mu     sync.Mutex
+	dir    string
+	file   *os.File
+	read   int64 // Offset of the next record to pop.
+	count  int
+	closed bool
+	signal chan struct{} // Receives a value when a message is pushed.
+}
+
+// Error of a push to a closed spill queue.
+var errSpillClosed = errors.New("spill queue closed")
+
+// Obtains an empty spill queue that will keep its file in dir.
+func newSpillQueue(dir string) *spillQueue {
+	return &spillQueue{
+		dir:    dir,
+		signal: make(chan struct{}, 1),
+	}
+}
+
+// Appends a message to the queue. Returns errSpillClosed once the queue is closed.
+func (q *spillQueue) push(message *proto.Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return errSpillClosed
+	}
+	if q.file == nil {
+		file, err := os.CreateTemp(q.dir, "chittychat-spill-*")
+		if err != nil {
+			return err
+		}
+		q.file = file
+	}
+	data, err := pb.Marshal(message)
+	if err != nil {
+		return err
+	}
+	record := binary.AppendUvarint(nil, uint64(len(data)))
+	record = append(record, data...)
+	_, err = q.file.Seek(0, io.SeekEnd)
+	if err == nil {
+		_, err = q.file.Write(record)
+	}
+	if err != nil {
+		return err
+	}
+	q.count++
+
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Removes and returns the oldest message. Returns nil if the queue is empty.
+func (q *spillQueue) pop() (*proto.Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.count == 0 {
+		return nil, nil
+	}
+	_, err := q.file.Seek(q.read, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	reader := &countingReader{r: q.file}
+	size, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, size)
+	_, err = io.ReadFull(reader, data)
+	if err != nil {
+		return nil, err
+	}
+	message := &proto.Message{}
+	err = pb.Unmarshal(data, message)
+	if err != nil {
+		return nil, err
+	}
+
+	q.read += reader.n
+	q.count--
+	if q.count == 0 {
+		q.read = 0
+		err = q.file.Truncate(0)
+	}
+	return message, err
+}
+
+// Reports the number of messages in the queue.
+func (q *spillQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.count
+}
+
+// Deletes the queue's file, if any, and stops the queue taking messages.
+func (q *spillQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	if q.file != nil {
+		q.file.Close()
+		os.Remove(q.file.Name())
+		q.file = nil
+	}
+	q.count = 0
+}
+
+// Counts the bytes read from r, so the queue knows where the next record starts.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	var b [1]byte
+	_, err := io.ReadFull(c, b[:])
+	return b[0], err
+}
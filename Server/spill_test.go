@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	proto "example/chittychat/grpc"
+	"fmt"
+	"os"
+	"testing"
+
+	pb "google.golang.org/protobuf/proto"
+)
+
+// Pushes chat text to a spill queue.
+func pushTest(t *testing.T, q *spillQueue, contents ...string) {
+	t.Helper()
+	for _, content := range contents {
+		err := q.push(&proto.Message{Payload: &proto.Message_Content{Content: content}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Pops what a spill queue holds, returning the contents.
+func popTest(t *testing.T, q *spillQueue) []string {
+	t.Helper()
+	var contents []string
+	for {
+		message, err := q.pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if message == nil {
+			return contents
+		}
+		contents = append(contents, message.GetContent())
+	}
+}
+
+// Messages come out in the order they went in, also when pushes and pops interleave,
+// and the file is emptied whenever the queue is.
+func TestSpillQueue(t *testing.T) {
+	q := newSpillQueue(t.TempDir())
+	defer q.close()
+	pushTest(t, q, "a", "b")
+	select {
+	case <-q.signal:
+	default:
+		t.Fatal("no signal after a push")
+	}
+	first, err := q.pop()
+	if err != nil || first.GetContent() != "a" {
+		t.Fatalf("popped %v, %v, want a", first, err)
+	}
+	pushTest(t, q, "c")
+	if q.len() != 2 {
+		t.Fatalf("%d message(s) queued, want 2", q.len())
+	}
+	if got := fmt.Sprint(popTest(t, q)); got != "[b c]" {
+		t.Fatalf("popped %v, want [b c]", got)
+	}
+	info, err := q.file.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 0 {
+		t.Fatalf("the file holds %d bytes once the queue is empty, want 0", info.Size())
+	}
+}
+
+// The file holds each message as its length, as a uvarint, followed by the message.
+func TestSpillFileFormat(t *testing.T) {
+	q := newSpillQueue(t.TempDir())
+	defer q.close()
+	pushTest(t, q, "hello", "world")
+	data, err := os.ReadFile(q.file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents []string
+	for len(data) > 0 {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < size {
+			t.Fatalf("bad record length at %v", data)
+		}
+		message := &proto.Message{}
+		err = pb.Unmarshal(data[n:n+int(size)], message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, message.GetContent())
+		data = data[n+int(size):]
+	}
+	if fmt.Sprint(contents) != "[hello world]" {
+		t.Fatalf("the file holds %v, want [hello world]", contents)
+	}
+}
+
+// Closing removes the file, and a push after that fails without creating another.
+func TestSpillQueueClosed(t *testing.T) {
+	dir := t.TempDir()
+	q := newSpillQueue(dir)
+	pushTest(t, q, "a")
+	q.close()
+	err := q.push(&proto.Message{Payload: &proto.Message_Content{Content: "b"}})
+	if !errors.Is(err, errSpillClosed) {
+		t.Fatalf("got %v pushing to a closed queue, want %v", err, errSpillClosed)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("%d file(s) left after closing", len(entries))
+	}
+}
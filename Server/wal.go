@@ -0,0 +1,351 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	proto "example/chittychat/grpc"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	pb "google.golang.org/protobuf/proto"
+)
+
+// Names of the fsync policies accepted by openMessageLog.
+const (
+	syncAlways   = "always"   // Sync after every record; nothing acknowledged is lost.
+	syncInterval = "interval" // Sync periodically; a crash loses at most the last interval.
+	syncNever    = "never"    // Leave it to the operating system.
+)
+
+// Kinds of records in the message log.
+const (
+	messageRecord byte = 'M' // A broadcast message, as a protobuf Message.
+	roomRecord    byte = 'R' // A room was created. The payload is its name.
+)
+
+// Checksums of the records in the message log.
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// A write-ahead, append-only log of the broadcasts in every room, and of room creation,
+// from which the server restores its rooms when it starts.
+//
+// Each record is its length as a uvarint, a CRC-32C checksum of the rest as 4 big-endian
+// bytes, and the record kind followed by its payload. A record cut short or failing its
+// checksum ends the log: it can only be the last, torn by a crash while being written,
+// and is cut off before new records are appended.
+type messageLog struct {
+	mu     sync.Mutex
+	file   *os.File
+	policy string
+	dirty  bool          // Written since the last sync.
+	stop   chan struct{} // Closed to stop the interval syncer.
+}
+
+// Opens the message log at path, creating it if it does not exist.
+// The policy decides how often appended records are synced to disk. With syncInterval,
+// a goroutine syncs every interval until the log is closed.
+func openMessageLog(path string, policy string, interval time.Duration) (*messageLog, error) {
+	switch policy {
+	case syncAlways, syncInterval, syncNever:
+	default:
+		return nil, fmt.Errorf("unknown sync policy '%s' (want %s, %s or %s)", policy, syncAlways, syncInterval, syncNever)
+	}
+	if policy == syncInterval && interval <= 0 {
+		return nil, fmt.Errorf("sync interval must be positive, not %v", interval)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	l := &messageLog{
+		file:   file,
+		policy: policy,
+		stop:   make(chan struct{}),
+	}
+	if policy == syncInterval {
+		go l.syncEvery(interval)
+	}
+	return l, nil
+}
+
+// Reads the log from the start, calling onRoom for every room created and onMessage for every
+// message, in the order they were appended. A torn record at the end, as a crash while
+// appending leaves, is cut off. Damage anywhere else is an error, so that intact records
+// after it are not thrown away.
+// Call this once, before appending.
+func (l *messageLog) replay(onRoom func(name string), onMessage func(message *proto.Message)) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, err := l.file.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	reader := &countingReader{r: bufio.NewReader(l.file)}
+	var good int64 // Offset after the last intact record.
+	for {
+		kind, payload, err := readRecord(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			torn, statErr := l.tornAt(good, reader.n, err)
+			if statErr != nil {
+				return statErr
+			}
+			if !torn {
+				return fmt.Errorf("message log damaged at offset %d: %v", good, err)
+			}
+			log.Printf("Warning: Message log ends in a torn record at offset %d (%v). Cutting it off there.\n", good, err)
+			break
+		}
+		switch kind {
+		case roomRecord:
+			onRoom(string(payload))
+		case messageRecord:
+			message := &proto.Message{}
+			err = pb.Unmarshal(payload, message)
+			if err != nil {
+				return fmt.Errorf("message log offset %d: %v", good, err)
+			}
+			onMessage(message)
+		default:
+			return fmt.Errorf("message log offset %d: unknown record kind %q", good, kind)
+		}
+		good = reader.n
+	}
+
+	err = l.file.Truncate(good)
+	if err == nil {
+		_, err = l.file.Seek(good, io.SeekStart)
+	}
+	return err
+}
+
+// Reports whether a record that could not be read, from offset start to end, is torn, as
+// a crash while appending leaves it: cut short, failing its checksum as the last thing in
+// the log, or with a length that would run it to the end of the log. A crash after the
+// file grew but before the record was written leaves zeros, which is a length of 0.
+// The caller must hold l.mu.
+func (l *messageLog) tornAt(start int64, end int64, err error) (bool, error) {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true, nil
+	}
+	var lengthErr *recordLengthError
+	if !errors.Is(err, errChecksum) && !errors.As(err, &lengthErr) {
+		return false, nil
+	}
+	info, statErr := l.file.Stat()
+	if statErr != nil {
+		return false, statErr
+	}
+	switch {
+	case lengthErr == nil:
+		return end == info.Size(), nil
+	case lengthErr.size > 0:
+		return lengthErr.end+4+int64(lengthErr.size) >= info.Size(), nil
+	default:
+		return l.zerosFrom(start, info.Size())
+	}
+}
+
+// Reports whether the log holds nothing but zeros from offset start to size.
+// The caller must hold l.mu.
+func (l *messageLog) zerosFrom(start int64, size int64) (bool, error) {
+	reader := bufio.NewReader(io.NewSectionReader(l.file, start, size-start))
+	for {
+		b, err := reader.ReadByte()
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if b != 0 {
+			return false, nil
+		}
+	}
+}
+
+// Error of a record whose checksum does not match its data.
+var errChecksum = errors.New("checksum mismatch")
+
+// Error of a record whose length cannot be right.
+type recordLengthError struct {
+	size uint64
+	end  int64 // Offset after the length, where the checksum would start.
+}
+
+func (e *recordLengthError) Error() string {
+	return fmt.Sprintf("bad record length %d", e.size)
+}
+
+// Reads a record, checking its checksum. Returns io.EOF at a clean end of the log,
+// and io.ErrUnexpectedEOF if the log ends within the record.
+func readRecord(reader *countingReader) (byte, []byte, error) {
+	start := reader.n
+	size, err := binary.ReadUvarint(reader)
+	if err == io.EOF && reader.n == start {
+		return 0, nil, io.EOF
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	if size == 0 || size > 1<<20 {
+		return 0, nil, &recordLengthError{size: size, end: reader.n}
+	}
+	var sum [4]byte
+	_, err = io.ReadFull(reader, sum[:])
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	data := make([]byte, size)
+	_, err = io.ReadFull(reader, data)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	if crc32.Checksum(data, crcTable) != binary.BigEndian.Uint32(sum[:]) {
+		return 0, nil, errChecksum
+	}
+	return data[0], data[1:], nil
+}
+
+// Appends a broadcast message to the log.
+func (l *messageLog) appendMessage(message *proto.Message) error {
+	payload, err := pb.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return l.append(messageRecord, payload)
+}
+
+// Appends the creation of a room to the log.
+func (l *messageLog) appendRoom(name string) error {
+	return l.append(roomRecord, []byte(name))
+}
+
+// Appends a record, syncing it to disk if the policy says so.
+func (l *messageLog) append(kind byte, payload []byte) error {
+	data := append([]byte{kind}, payload...)
+	record := binary.AppendUvarint(nil, uint64(len(data)))
+	record = binary.BigEndian.AppendUint32(record, crc32.Checksum(data, crcTable))
+	record = append(record, data...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := l.file.Write(record)
+	if err != nil {
+		return err
+	}
+	l.dirty = true
+	if l.policy == syncAlways {
+		return l.sync()
+	}
+	return nil
+}
+
+// Syncs the log to disk if it was written since the last sync.
+// The caller must hold l.mu.
+func (l *messageLog) sync() error {
+	if !l.dirty {
+		return nil
+	}
+	l.dirty = false
+	return l.file.Sync()
+}
+
+// Go-routine syncing the log every interval, until it is closed.
+func (l *messageLog) syncEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.mu.Lock()
+			err := l.sync()
+			l.mu.Unlock()
+			if err != nil {
+				log.Printf("Warning: Failed to sync message log: %v\n", err)
+			}
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// Syncs and closes the log.
+func (l *messageLog) close() error {
+	close(l.stop)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dirty = true // Sync whatever the policy, so a clean stop loses nothing.
+	err := l.sync()
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Restores the rooms from the message log: each room's recent messages, its sequence number,
+// and its clock, which witnesses every message so that timestamps never go backwards
+// across restarts. The messages are indexed for search again as well, and posts with
+// message ids go back in the dedup window, with the confirm they got: it was stamped with
+// the tick right after the broadcast. A post sent again after the restart is then not
+// broadcast twice, and gets the same confirm as the first time.
+// Shutdown events from earlier runs are marked as restored, so nobody takes them as news.
+func (s *ChittyChatServer) restore() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	err := s.wal.replay(func(name string) {
+		s.restoredRoom(name)
+	}, func(message *proto.Message) {
+		r := s.restoredRoom(message.Room)
+		if shutdown := message.GetShutdown(); shutdown != nil {
+			shutdown.Restored = true
+		}
+		r.setTime(message.Timestamp())
+		r.sequence = max(r.sequence, message.Sequence)
+		r.recent.add(message)
+		s.index.add(message)
+		s.dedup.remember(message.Author, message.MessageId, &proto.Confirm{
+			Author:    s.name,
+			LamportTs: message.LamportTs + 1,
+			VectorTs:  message.VectorTs,
+			Room:      r.name,
+		})
+		count++
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Restored %d message(s) in %d room(s) from the message log.\n", count, len(s.rooms))
+	return nil
+}
+
+// Obtains a room being restored, creating it if it does not exist yet.
+// The caller must hold s.mu.
+func (s *ChittyChatServer) restoredRoom(name string) *room {
+	if name == "" {
+		name = defaultRoom
+	}
+	r := s.rooms[name]
+	if r == nil {
+		r = s.newRoom(name)
+		s.rooms[name] = r
+	}
+	return r
+}
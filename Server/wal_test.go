@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/binary"
+	proto "example/chittychat/grpc"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Writes a message log of three messages, and returns its path and the offset each record ends at.
+func writeTestLog(t *testing.T) (string, []int64) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "chitty.wal")
+	l, err := openMessageLog(path, syncAlways, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ends []int64
+	for n := 1; n <= 3; n++ {
+		message := &proto.Message{
+			Payload:  &proto.Message_Content{Content: fmt.Sprintf("message %d", n)},
+			Author:   "alice",
+			Sequence: uint64(n),
+		}
+		err = l.appendMessage(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := l.file.Stat()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ends = append(ends, info.Size())
+	}
+	err = l.close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, ends
+}
+
+// Replays a message log, returning the sequence numbers of its messages and its size after.
+func replayTestLog(t *testing.T, path string) ([]uint64, int64, error) {
+	t.Helper()
+	l, err := openMessageLog(path, syncAlways, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.close()
+	var sequences []uint64
+	err = l.replay(func(string) {}, func(message *proto.Message) {
+		sequences = append(sequences, message.Sequence)
+	})
+	info, statErr := l.file.Stat()
+	if statErr != nil {
+		t.Fatal(statErr)
+	}
+	return sequences, info.Size(), err
+}
+
+func TestReplayDamage(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(data []byte, ends []int64) []byte
+		want   []uint64 // Nil if replaying fails.
+	}{
+		{"intact", func(data []byte, ends []int64) []byte { return data }, []uint64{1, 2, 3}},
+		{"cut short", func(data []byte, ends []int64) []byte { return data[:ends[2]-3] }, []uint64{1, 2}},
+		{"cut after the length", func(data []byte, ends []int64) []byte { return data[:ends[1]+1] }, []uint64{1, 2}},
+		{"last record corrupt", func(data []byte, ends []int64) []byte {
+			data[ends[2]-1] ^= 0xff
+			return data
+		}, []uint64{1, 2}},
+		{"middle record corrupt", func(data []byte, ends []int64) []byte {
+			data[ends[1]-1] ^= 0xff
+			return data
+		}, nil},
+		{"zeros after the last record", func(data []byte, ends []int64) []byte {
+			return append(data, make([]byte, 100)...)
+		}, []uint64{1, 2, 3}},
+		{"zeros in place of the last record", func(data []byte, ends []int64) []byte {
+			clear(data[ends[1]:])
+			return data
+		}, []uint64{1, 2}},
+		{"zeros in place of the middle record", func(data []byte, ends []int64) []byte {
+			clear(data[ends[0]:ends[1]])
+			return data
+		}, nil},
+		{"length past the end", func(data []byte, ends []int64) []byte {
+			data = binary.AppendUvarint(data, 2<<20)
+			return append(data, 1, 2, 3)
+		}, []uint64{1, 2, 3}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, ends := writeTestLog(t)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			damaged := test.damage(data, ends)
+			err = os.WriteFile(path, damaged, 0o600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, size, err := replayTestLog(t, path)
+			if test.want == nil {
+				if err == nil {
+					t.Fatalf("replayed %v from a damaged log, want an error", got)
+				}
+				if size != int64(len(damaged)) {
+					t.Fatalf("log cut to %d bytes, want it left at %d", size, len(damaged))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(test.want) {
+				t.Fatalf("replayed %v, want %v", got, test.want)
+			}
+			if want := ends[len(test.want)-1]; size != want {
+				t.Fatalf("log cut to %d bytes, want %d", size, want)
+			}
+		})
+	}
+}
+
+func TestOpenMessageLogInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chitty.wal")
+	for _, interval := range []time.Duration{0, -time.Second} {
+		l, err := openMessageLog(path, syncInterval, interval)
+		if err == nil {
+			l.close()
+			t.Fatalf("opened the log syncing every %v, want an error", interval)
+		}
+	}
+	l, err := openMessageLog(path, syncNever, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.close()
+}
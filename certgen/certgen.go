@@ -0,0 +1,134 @@
+// Certgen generates a local certificate authority, and certificates signed by it, for running
+// ChittyChat over TLS during development and tests. Not for production use.
+//
+// Writes to the output directory:
+//   - ca.pem, ca-key.pem: the certificate authority.
+//   - server.pem, server-key.pem: the server's certificate, valid for the given hosts.
+//   - <callsign>.pem, <callsign>-key.pem: a client certificate per callsign, for mutual TLS.
+//     The common name of a client certificate is the callsign.
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var outDir = flag.String("out", "certs", "directory to write certificates and keys to")
+var hosts = flag.String("hosts", "localhost,127.0.0.1", "comma-separated host names and IP addresses the server certificate is valid for")
+var clients = flag.String("clients", "", "comma-separated callsigns to issue client certificates for")
+var validFor = flag.Duration("valid-for", 365*24*time.Hour, "validity period of the certificates")
+
+// Start point for program.
+func main() {
+	flag.Parse()
+
+	err := os.MkdirAll(*outDir, 0o755)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	ca, caKey := issue(pkix.Name{CommonName: "ChittyChat Development CA"}, nil, nil, func(template *x509.Certificate) {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	})
+	write("ca", ca, caKey)
+
+	server, serverKey := issue(pkix.Name{CommonName: "ChittyServer"}, ca, caKey, func(template *x509.Certificate) {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		for _, host := range splitList(*hosts) {
+			if ip := net.ParseIP(host); ip != nil {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			} else {
+				template.DNSNames = append(template.DNSNames, host)
+			}
+		}
+	})
+	write("server", server, serverKey)
+
+	for _, callsign := range splitList(*clients) {
+		cert, key := issue(pkix.Name{CommonName: callsign}, ca, caKey, func(template *x509.Certificate) {
+			template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+		})
+		write(callsign, cert, key)
+	}
+}
+
+// Creates a key pair and a certificate for it, signed by the parent certificate and key.
+// With a nil parent, the certificate is self-signed. The template is adjusted by customize.
+func issue(subject pkix.Name, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, customize func(*x509.Certificate)) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(*validFor),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	customize(template)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	return cert, key
+}
+
+// Writes a certificate to <name>.pem and its key to <name>-key.pem in the output directory.
+func write(name string, cert *x509.Certificate, key *ecdsa.PrivateKey) {
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	certFile := filepath.Join(*outDir, name+".pem")
+	keyFile := filepath.Join(*outDir, name+"-key.pem")
+
+	err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0o644)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	fmt.Printf("wrote %s and %s\n", certFile, keyFile)
+}
+
+// Splits a comma-separated list, dropping empty entries.
+func splitList(list string) []string {
+	var entries []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
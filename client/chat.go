@@ -0,0 +1,114 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	proto "example/chittychat/grpc"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Number of times a post is sent again after losing the connection before it was acknowledged,
+// and how long to wait for a reconnect each time.
+const postRetries = 3
+const retryWait = 10 * time.Second
+
+// Our Chat stream. Our posts go out on it, and their acknowledgements come back on it
+// in between the messages of the chat.
+type chatStream struct {
+	stream  grpc.BidiStreamingClient[proto.ChatRequest, proto.ChatResponse]
+	mu      sync.Mutex // Guards sending, and the fields below.
+	lastID  uint64
+	pending map[uint64]chan *proto.ChatAck // Of the posts waiting for acknowledgement, by request id.
+	closed  bool
+}
+
+// The stream we are on, or nil while we are reconnecting.
+var activeChat atomic.Pointer[chatStream]
+
+// Waits up to retryWait for us to be on a stream other than the one lost.
+// Returns nil if we are not.
+func awaitChat(lost *chatStream) *chatStream {
+	deadline := time.Now().Add(retryWait)
+	for time.Now().Before(deadline) && !leaving.Load() {
+		if chat := activeChat.Load(); chat != nil && chat != lost {
+			return chat
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil
+}
+
+// Generates a random message id for a post.
+func newMessageID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Obtains a Chat stream that has been opened.
+func newChatStream(stream grpc.BidiStreamingClient[proto.ChatRequest, proto.ChatResponse]) *chatStream {
+	return &chatStream{
+		stream:  stream,
+		pending: make(map[uint64]chan *proto.ChatAck),
+	}
+}
+
+// Posts a message on the stream, and waits for its acknowledgement.
+// Returns the confirm, or the status error the post failed with. The error is
+// codes.Unavailable if the stream ended before the post was acknowledged.
+func (c *chatStream) post(message *proto.Message) (*proto.Confirm, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, status.Error(codes.Unavailable, "Not connected.")
+	}
+	c.lastID++
+	id := c.lastID
+	acked := make(chan *proto.ChatAck, 1)
+	c.pending[id] = acked
+	err := c.stream.Send(&proto.ChatRequest{
+		Id:   id,
+		Kind: &proto.ChatRequest_Post{Post: message},
+	})
+	c.mu.Unlock()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, "Not connected.")
+	}
+
+	ack, ok := <-acked
+	if !ok {
+		return nil, status.Error(codes.Unavailable, "Lost connection before the post was acknowledged.")
+	}
+	if codes.Code(ack.Code) != codes.OK {
+		return nil, status.Error(codes.Code(ack.Code), ack.Error)
+	}
+	return ack.Confirm, nil
+}
+
+// Hands an acknowledgement to the post waiting for it.
+func (c *chatStream) acknowledge(ack *proto.ChatAck) {
+	c.mu.Lock()
+	acked := c.pending[ack.Id]
+	delete(c.pending, ack.Id)
+	c.mu.Unlock()
+	if acked != nil {
+		acked <- ack
+	}
+}
+
+// Marks the stream as ended, failing the posts still waiting for acknowledgement.
+func (c *chatStream) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for id, acked := range c.pending {
+		close(acked)
+		delete(c.pending, id)
+	}
+}
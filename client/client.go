@@ -3,51 +3,188 @@ package main
 import (
 	"bufio"
 	"context"
+	"example/chittychat/clock"
+	"example/chittychat/config"
 	proto "example/chittychat/grpc"
+	"flag"
 	"fmt"
-	"io"
 	"log"
+	"math/rand"
 	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 
+	"golang.org/x/term"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
+// Delays between attempts to join again after losing the stream.
+// The delay doubles with every failed attempt, up to the maximum.
+const initialBackoff = 500 * time.Millisecond
+const maxBackoff = 30 * time.Second
+
+// Time to wait for the server to confirm that we left, so that leaving never hangs.
+const leaveTimeout = 5 * time.Second
+
+// Metadata keys of the session token and of our credentials.
+const sessionTokenKey = "session-token"
+const usernameKey = "username"
+const passwordKey = "password"
+
 var stdIn = setScanner()
 var ctx context.Context = context.Background()
 
 var name string
-var lamportTime int64
+
+// Password sent with every call, if the server authenticates calls.
+var password string
+
+// Token of our session, issued by the server when joining. Sent with every later call.
+var sessionToken atomic.Value
+
+// Flags not given on the command line are taken from environment variables with this
+// prefix, e.g. CHITTYCHAT_CLIENT_ADDRESS, and then from the -config file.
+const envPrefix = "CHITTYCHAT_CLIENT_"
+
+var address = flag.String("address", "localhost:5050", "network address of the server")
+var logFile = flag.String("log-file", "", "file to write the log to (<callsign>.txt if empty)")
+var configFile = flag.String(config.FileFlag, "", "JSON config file with flag values, keyed by flag name")
+var printConfig = flag.Bool("print-config", false, "print the effective configuration as JSON and exit")
+var roomName = flag.String("room", "general", "room to join")
+var delivery = flag.String("delivery", "fifo", "order in which messages are shown: fifo (as received), causal or total (same order for every participant)")
+var clockKind = flag.String("clock", clock.VectorKind, "logical clock: lamport, vector or hybrid")
+var tlsCA = flag.String("tls-ca", "", "CA file for verifying the server's certificate; enables TLS")
+var tlsCert = flag.String("tls-cert", "", "client certificate file for mutual TLS; its common name is used as callsign")
+var tlsKey = flag.String("tls-key", "", "key file for -tls-cert")
+var tlsServerName = flag.String("tls-server-name", "", "name to verify the server's certificate against, if not the host")
+
+// Shared by the input loop and the stream poller. Clocks are safe for concurrent use.
+var clocks *roomClocks
+
+// Set once we are leaving, so a closed stream is not taken as a lost connection.
+var leaving atomic.Bool
+
+// Sequence number of the last broadcast shown. Sent when joining again, to resume from there.
+var lastSequence atomic.Uint64
+
+// Timestamps and author of the last message shown, for reporting causality.
+var lastVector clock.Vector
+var lastHybrid clock.Timestamp
+var lastAuthor string
 
 // Start point for program.
 func main() {
-	fmt.Print("Enter your callsign and press ENTER: ")
-	name = nextLine()
+	flag.Parse()
+	err := config.Load(flag.CommandLine, envPrefix)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if *printConfig {
+		config.Print(os.Stdout, flag.CommandLine, []string{"print-config"}, nil)
+		return
+	}
+	newDeliveryQueue(*delivery) // Fail early on an unknown mode.
+	if *delivery == "causal" && *clockKind != clock.VectorKind {
+		// Our posts would carry no vector, and would be shown as if in fifo order.
+		log.Fatalf("-delivery causal needs -clock %s, not %s", clock.VectorKind, *clockKind)
+	}
+
+	creds, err := transportCredentials(*tlsCA, *tlsCert, *tlsKey, *tlsServerName)
+	if err != nil {
+		log.Fatalf("Failed to set up TLS: %v", err)
+	}
 
-	logfile, err := os.Create(name + ".txt")
+	if *tlsCert != "" {
+		name, err = certificateName(*tlsCert, *tlsKey)
+		if err != nil {
+			log.Fatalf("Failed to read certificate: %v", err)
+		}
+		fmt.Printf("Your callsign is %s, from your certificate.\n", name)
+	} else {
+		fmt.Print("Enter your callsign and press ENTER: ")
+		name = nextLine()
+	}
+	fmt.Print("Enter your password and press ENTER (empty if the server needs none): ")
+	password = readPassword()
+
+	clocks, err = newRoomClocks(*clockKind, name)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	setCurrentRoom(*roomName)
+
+	if *logFile == "" {
+		*logFile = name + ".txt"
+	}
+	logfile, err := os.Create(*logFile)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
 	log.SetOutput(logfile)
 
-	runChatService()
+	runChatService(creds)
 }
 
 // Overall method for running the chat service.
-func runChatService() {
-	conn := getConnectionToServer()
+func runChatService(creds credentials.TransportCredentials) {
+	conn := getConnectionToServer(creds)
 	defer conn.Close()
 
 	client := proto.NewChittyChatServiceClient(conn)
-	stream := joinChatBoard(client)
+	chat, queue, err := joinChatBoard(client)
+	if err != nil {
+		notify("Failed to join: %v", err)
+		os.Exit(1)
+	}
 
-	go pollStream(stream)
+	go stayConnected(client, chat, queue)
+	go leaveOnSignal(client)
 	handleUserInput(client)
 }
 
+// Go-routine leaving the chat when the program is interrupted, e.g. by Ctrl+C.
+// Only the first signal is caught: a second one kills the program while it is leaving.
+func leaveOnSignal(client proto.ChittyChatServiceClient) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	signal.Stop(signals)
+	leaveChatBoard(client, "")
+	os.Exit(0)
+}
+
+// Leaves the chat board, with an optional farewell for the other participants.
+// If the server does not confirm within leaveTimeout, we go without its confirm.
+// Like joining, leaving does not tick the clock; the server stamps the announcement after it.
+func leaveChatBoard(client proto.ChittyChatServiceClient, farewell string) {
+	leaving.Store(true)
+	msg := proto.Message{
+		Payload: &proto.Message_Content{Content: farewell},
+		Author:  name,
+	}
+	msg.SetTimestamp(currentClock().Now())
+	leaveCtx, cancel := context.WithTimeout(callContext(), leaveTimeout)
+	defer cancel()
+	confirm, err := client.LeaveMessageBoard(leaveCtx, &msg)
+	if err != nil {
+		log.Printf("Failed to leave: %v\n", err)
+		return
+	}
+	currentClock().Witness(confirm.Timestamp())
+	log.Println("Left the chat. Bye!")
+}
+
 // Establishes connection to the server.
-func getConnectionToServer() *grpc.ClientConn {
-	conn, err := grpc.NewClient("localhost:5050", grpc.WithTransportCredentials(insecure.NewCredentials()))
+func getConnectionToServer(creds credentials.TransportCredentials) *grpc.ClientConn {
+	conn, err := grpc.NewClient(*address, grpc.WithTransportCredentials(creds))
 	if err != nil {
 		log.Fatalf("Failed to obtain connection: %v", err)
 	}
@@ -55,73 +192,338 @@ func getConnectionToServer() *grpc.ClientConn {
 }
 
 // Obtains a proto.Confirm to send.
+// The clock is not ticked: joining is not an event other participants need to
+// have delivered before ours, and a vector entry for it would never be delivered.
 func confirmMessage() *proto.Confirm {
-	return &proto.Confirm{
-		Author:    name,
-		LamportTs: getTime(),
+	confirm := &proto.Confirm{
+		Author:      name,
+		ResumeAfter: lastSequence.Load(),
+		Room:        currentRoom(),
+	}
+	confirm.SetTimestamp(currentClock().Now())
+	return confirm
+}
+
+// Obtains the context for a call to the server, carrying our credentials if we have a
+// password, and our session token once we have one.
+func callContext() context.Context {
+	callCtx := ctx
+	if password != "" {
+		callCtx = metadata.AppendToOutgoingContext(callCtx, usernameKey, name, passwordKey, password)
 	}
+	token, _ := sessionToken.Load().(string)
+	if token == "" {
+		return callCtx
+	}
+	return metadata.AppendToOutgoingContext(callCtx, sessionTokenKey, token)
 }
 
-// Joins the chat board. Returns a stream of posted messages from the chat.
-func joinChatBoard(client proto.ChittyChatServiceClient) grpc.ServerStreamingClient[proto.Message] {
-	stream, err := client.JoinMessageBoard(ctx, confirmMessage())
+// Joins the chat board on a Chat stream, which then becomes the one we post on.
+// Returns the stream, and a fresh delivery queue for the messages from the chat.
+// Joining again with the token of an earlier session keeps our callsign reserved for us.
+// The welcome message is received before returning, so our clocks have caught up
+// with the server before we post anything.
+func joinChatBoard(client proto.ChittyChatServiceClient) (*chatStream, deliveryQueue, error) {
+	stream, err := client.Chat(callContext())
+	if err != nil {
+		return nil, nil, err
+	}
+	err = stream.Send(&proto.ChatRequest{Kind: &proto.ChatRequest_Join{Join: confirmMessage()}})
 	if err != nil {
-		log.Fatalf("Failed to obtain stream: %v", err)
+		_, err = stream.Recv() // The reason the stream ended.
+		return nil, nil, err
 	}
-	return stream
+	header, err := stream.Header()
+	if err != nil {
+		return nil, nil, err
+	}
+	if token := header.Get(sessionTokenKey); len(token) > 0 {
+		sessionToken.Store(token[0])
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		return nil, nil, err
+	}
+	welcome := first.GetMessage()
+	if welcome == nil {
+		return nil, nil, status.Error(codes.Internal, "The stream did not start with a welcome.")
+	}
+	setCurrentRoom(welcome.Room)
+	queue := newRoomQueue(*delivery)
+	receiveMessage(welcome, queue)
+	chat := newChatStream(stream)
+	activeChat.Store(chat)
+	return chat, queue, nil
 }
 
-// Go-routine for polling stream from server and displaying messages from the chat board.
-func pollStream(stream grpc.ServerStreamingClient[proto.Message]) {
+// Go-routine keeping us on the chat board. Polls the stream, and when it breaks,
+// joins again under the same callsign, resuming from the last message shown.
+func stayConnected(client proto.ChittyChatServiceClient, chat *chatStream, queue deliveryQueue) {
 	for {
-		msg, err := stream.Recv()
-		if err == io.EOF {
-			log.Println("Stream closed. Bye!")
-			break
-		} else if err != nil {
-			log.Fatal(err)
+		err := pollStream(chat, queue)
+		if leaving.Load() {
+			return
+		}
+		notify("Lost connection to the chat: %v", err)
+		chat, queue = rejoinChatBoard(client)
+		notify("Reconnected to the chat.")
+	}
+}
+
+// Joins the chat board again, retrying with exponential backoff until it succeeds.
+// If our room is gone, e.g. as the server was restarted, we join the default room instead.
+func rejoinChatBoard(client proto.ChittyChatServiceClient) (*chatStream, deliveryQueue) {
+	for attempt := 0; ; attempt++ {
+		delay := backoff(attempt)
+		notify("Reconnecting in %v...", delay.Round(time.Millisecond))
+		time.Sleep(delay)
+
+		chat, queue, err := joinChatBoard(client)
+		if err == nil {
+			return chat, queue
+		}
+		log.Printf("Failed to join: %v\n", err)
+		if status.Code(err) == codes.NotFound {
+			notify("Room %s is gone. Joining the default room instead.", currentRoom())
+			setCurrentRoom("")
+			lastSequence.Store(0)
+		}
+	}
+}
+
+// Obtains the delay before a reconnect attempt. It doubles from initialBackoff with each attempt
+// up to maxBackoff, and is then randomized to between half and all of that, so that clients
+// that lost the same server do not all come back at once.
+func backoff(attempt int) time.Duration {
+	delay := maxBackoff
+	if attempt < 16 {
+		delay = min(initialBackoff<<attempt, maxBackoff)
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+}
+
+// Polls the stream from server, displays messages from the chat board, and hands
+// acknowledgements to the posts waiting for them.
+// Returns the error that ended the stream; io.EOF if the server closed it.
+func pollStream(chat *chatStream, queue deliveryQueue) error {
+	for {
+		response, err := chat.stream.Recv()
+		if err != nil {
+			activeChat.CompareAndSwap(chat, nil)
+			chat.close()
+			return err
+		}
+		switch kind := response.Kind.(type) {
+		case *proto.ChatResponse_Message:
+			receiveMessage(kind.Message, queue)
+		case *proto.ChatResponse_Ack:
+			chat.acknowledge(kind.Ack)
+		}
+	}
+}
+
+// Passes a message from the stream through the delivery queue and displays what it releases.
+// The clock only takes in a message once it is delivered, so that our own posts
+// never claim to depend on messages we have not yet shown.
+func receiveMessage(msg *proto.Message, queue deliveryQueue) {
+	for _, ready := range queue.push(msg) {
+		clocks.in(ready.Room).Witness(ready.Timestamp())
+		if ready.Sequence > lastSequence.Load() {
+			lastSequence.Store(ready.Sequence)
+		}
+		printMessage(ready)
+		if shutdown := ready.GetShutdown(); shutdown != nil && !shutdown.Restored {
+			notify("The server is shutting down.")
 		}
-		setTime(msg.LamportTs)
-		printMessage(msg)
 	}
 }
 
 // Main routine for accepting terminal input as messages to be posted.
+// Returns when the user types /quit, optionally followed by a farewell.
+// /rooms lists the rooms, /create <room> creates one and /join <room> moves us there.
+// /msg <callsign> <text> sends a direct message, /history shows earlier messages,
+// /search <query> finds messages, /who lists the participants, and /away and /back set our status.
 func handleUserInput(client proto.ChittyChatServiceClient) {
 	for {
 		input := nextLine()
 		if len(input) == 0 {
 			continue
 		}
-		msg := proto.Message{
-			Content:   input,
-			Author:    name,
-			LamportTs: getTime(),
+		command, arg, _ := strings.Cut(input, " ")
+		arg = strings.TrimSpace(arg)
+		var err error
+		switch command {
+		case "/quit":
+			leaveChatBoard(client, arg)
+			return
+		case "/rooms":
+			err = listRooms(client)
+		case "/create":
+			err = createRoom(client, arg)
+		case "/join":
+			err = joinRoom(client, arg)
+		case "/search":
+			err = searchMessages(client, arg)
+		case "/history":
+			err = showHistory(client, arg)
+		case "/who":
+			err = listParticipants(client)
+		case "/away":
+			err = setStatus(client, proto.Presence_AWAY)
+		case "/back":
+			err = setStatus(client, proto.Presence_ONLINE)
+		case "/msg":
+			recipient, text, _ := strings.Cut(arg, " ")
+			err = sendDirectMessage(client, recipient, strings.TrimSpace(text))
+		default:
+			postMessage(input)
+			continue
 		}
-		confirm, err := client.PostMessage(ctx, &msg)
 		if err != nil {
-			log.Fatal(err)
+			notify("%s failed: %s", command, status.Convert(err).Message())
+		}
+	}
+}
+
+// Posts a message in the room we are in, on our Chat stream.
+// The post is stamped with the next time of our clock, but the clock only advances once
+// the post is confirmed: a post the server refuses must not leave a gap in our vector
+// entry, or participants in causal mode would hold back our later posts waiting for it.
+// If the connection is lost before the post is acknowledged, it is sent again once we
+// have reconnected, with the same message id, so the server broadcasts it at most once.
+// A post the server refuses, e.g. for being too long, is reported and we carry on.
+func postMessage(input string) {
+	chat := activeChat.Load()
+	if chat == nil {
+		notify("Not connected, message not sent: %s", input)
+		return
+	}
+	clk := currentClock()
+	msg := proto.Message{
+		Payload:   &proto.Message_Content{Content: input},
+		Author:    name,
+		Room:      currentRoom(),
+		MessageId: newMessageID(),
+	}
+	ts := clk.Next()
+	msg.SetTimestamp(ts)
+	confirm, err := chat.post(&msg)
+	for retry := 0; retry < postRetries && status.Code(err) == codes.Unavailable; retry++ {
+		chat = awaitChat(chat)
+		if chat == nil || currentRoom() != msg.Room {
+			break
 		}
-		setTime(confirm.LamportTs)
+		log.Printf("Sending message %s again.\n", msg.MessageId)
+		confirm, err = chat.post(&msg)
+	}
+	if status.Code(err) == codes.Unavailable {
+		notify("Not connected, message not sent: %s", input)
+		return
+	} else if err != nil {
+		notify("Message not sent: %s", status.Convert(err).Message())
+		return
 	}
+	clk.Witness(ts)
+	clk.Witness(confirm.Timestamp())
 }
 
-// Gets the next Lamport timestamp.
-func getTime() int64 {
-	lamportTime++
-	return lamportTime
+// Sends a direct message to one participant. The server echoes it to our stream.
+// Like joining, this does not tick the clock, as the others in the room never see
+// the message; the server stamps it after our clock.
+func sendDirectMessage(client proto.ChittyChatServiceClient, recipient string, text string) error {
+	if recipient == "" || text == "" {
+		return fmt.Errorf("usage: /msg <callsign> <text>")
+	}
+	clk := currentClock()
+	msg := proto.Message{
+		Payload:   &proto.Message_Content{Content: text},
+		Author:    name,
+		Recipient: recipient,
+	}
+	msg.SetTimestamp(clk.Now())
+	confirm, err := client.SendDirectMessage(callContext(), &msg)
+	if err != nil {
+		return err
+	}
+	clk.Witness(confirm.Timestamp())
+	return nil
+}
+
+// Tells the user about the connection, both in the terminal and in the log.
+func notify(format string, args ...any) {
+	fmt.Printf(format+"\n", args...)
+	log.Printf(format+"\n", args...)
+}
+
+// Formats a vector timestamp as <alice:2 bob:1>, sorted by name.
+func formatVector(vector clock.Vector) string {
+	names := make([]string, 0, len(vector))
+	for author := range vector {
+		names = append(names, author)
+	}
+	sort.Strings(names)
+	entries := make([]string, len(names))
+	for i, author := range names {
+		entries[i] = fmt.Sprintf("%s:%d", author, vector[author])
+	}
+	return "<" + strings.Join(entries, " ") + ">"
 }
 
-// Updates the Lamport timestamp to reflect an incoming timestamp.
-func setTime(in int64) {
-	if lamportTime < in {
-		lamportTime = in
+// Formats the hybrid part of a timestamp as the local wall time it was sent,
+// followed by the logical counter when several events share that wall time.
+func formatHybrid(ts clock.Timestamp) string {
+	wall := ts.WallTime().Format("15:04:05.000")
+	if ts.Logical > 0 {
+		return fmt.Sprintf("%s+%d", wall, ts.Logical)
 	}
+	return wall
 }
 
 // Prints the standard chat message format to console.
+// Events announced by the server are shown apart from chat text, as formatPayload says.
+// Direct messages are marked as such, and only carry a Lamport timestamp.
+// Earlier messages replayed on joining a room are marked too, and not compared.
+// Messages carrying a hybrid timestamp are shown with the time they were sent.
+//
+// Messages carrying a vector timestamp are also compared to the previous one shown,
+// since a Lamport timestamp alone cannot tell concurrent messages apart. Hybrid timestamps
+// are compared by their logical order rather than the rounded wall time displayed.
 func printMessage(message *proto.Message) {
-	log.Printf("%d %s: %s\n", message.LamportTs, message.Author, message.Content)
+	ts := message.Timestamp()
+	if message.Replayed {
+		log.Printf("(earlier) %d %s\n", ts.Lamport, formatPayload(message))
+		return
+	}
+	if message.Recipient != "" {
+		log.Printf("%d %s -> %s (direct): %s\n", ts.Lamport, message.Author, message.Recipient, message.GetContent())
+		return
+	}
+	if ts.IsHybrid() {
+		log.Printf("%d [%s] %s\n", ts.Lamport, formatHybrid(ts), formatPayload(message))
+	} else {
+		log.Printf("%d %s\n", ts.Lamport, formatPayload(message))
+	}
+
+	if len(ts.Vector) > 0 {
+		if lastVector != nil {
+			switch lastVector.Compare(ts.Vector) {
+			case clock.Before:
+				log.Printf("    %s happened after the previous message from %s\n", formatVector(ts.Vector), lastAuthor)
+			case clock.After:
+				log.Printf("    %s happened before the previous message from %s\n", formatVector(ts.Vector), lastAuthor)
+			case clock.Concurrent:
+				log.Printf("    %s is concurrent with the previous message from %s\n", formatVector(ts.Vector), lastAuthor)
+			}
+		}
+		lastVector = ts.Vector
+	}
+	if ts.IsHybrid() {
+		if lastHybrid.IsHybrid() && ts.HybridBefore(lastHybrid) {
+			log.Printf("    sent before the previous message from %s (%s)\n", lastAuthor, formatHybrid(lastHybrid))
+		}
+		lastHybrid = ts
+	}
+	lastAuthor = message.Author
 }
 
 // Setup for stdIn (input from console). Any scanner settings go here.
@@ -135,3 +537,17 @@ func nextLine() string {
 	stdIn.Scan()
 	return stdIn.Text()
 }
+
+// Obtains a password from stdIn, without echoing it if stdIn is a terminal.
+func readPassword() string {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nextLine()
+	}
+	input, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		log.Fatalf("Failed to read password: %v", err)
+	}
+	return string(input)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	proto "example/chittychat/grpc"
+	"fmt"
+	"time"
+)
+
+// Formats what a message says: "author: text" for chat text, and a line starting with ***
+// for an event announced by the server, which no participant can post.
+func formatPayload(message *proto.Message) string {
+	switch payload := message.Payload.(type) {
+	case *proto.Message_Joined:
+		return fmt.Sprintf("*** %s joined %s.", payload.Joined.Callsign, message.Room)
+	case *proto.Message_Left:
+		if payload.Left.Farewell != "" {
+			return fmt.Sprintf("*** %s left %s: %s", payload.Left.Callsign, message.Room, payload.Left.Farewell)
+		}
+		return fmt.Sprintf("*** %s left %s.", payload.Left.Callsign, message.Room)
+	case *proto.Message_Welcome:
+		line := fmt.Sprintf("*** Welcome to ChittyChat, %s! You are in %s.", payload.Welcome.Callsign, message.Room)
+		if payload.Welcome.Unavailable > 0 {
+			line += fmt.Sprintf(" %d message(s) since you left are no longer available.", payload.Welcome.Unavailable)
+		}
+		return line
+	case *proto.Message_Kicked:
+		return fmt.Sprintf("*** %s was disconnected: %s", payload.Kicked.Callsign, payload.Kicked.Reason)
+	case *proto.Message_Shutdown:
+		if payload.Shutdown.Restored {
+			return "*** The server shut down here."
+		}
+		drain := time.Duration(payload.Shutdown.DrainTimeoutMs) * time.Millisecond
+		return fmt.Sprintf("*** The server is shutting down. Everyone is disconnected within %v.", drain)
+	case *proto.Message_Dropped:
+		return fmt.Sprintf("*** %d message(s) were not delivered to you, as your connection fell behind.", payload.Dropped.Count)
+	}
+	return message.Author + ": " + message.GetContent()
+}
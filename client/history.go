@@ -0,0 +1,74 @@
+package main
+
+import (
+	proto "example/chittychat/grpc"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Number of messages /history shows at a time.
+const historyPageSize = 20
+
+// The last history request, and the cursor continuing it. Only used by the input loop.
+var lastHistoryRequest *proto.HistoryRequest
+
+// Shows earlier messages of the room we are in, a page at a time, going back in time.
+// The arguments are filters: from:<callsign>, since:<lamport> and until:<lamport>.
+// "more" continues the previous request with older messages.
+func showHistory(client proto.ChittyChatServiceClient, args string) error {
+	request, err := historyRequest(args)
+	if err != nil {
+		return err
+	}
+	page, err := client.GetHistory(callContext(), request)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range page.Messages {
+		log.Printf("(history) %d %s\n", message.LamportTs, formatPayload(message))
+	}
+	lastHistoryRequest = request
+	lastHistoryRequest.Cursor = page.NextCursor
+	if page.NextCursor == "" {
+		notify("%d earlier message(s) in your log. That is all.", len(page.Messages))
+	} else {
+		notify("%d earlier message(s) in your log. Type /history more for older ones.", len(page.Messages))
+	}
+	return nil
+}
+
+// Parses the arguments of /history into a request.
+func historyRequest(args string) (*proto.HistoryRequest, error) {
+	if args == "more" {
+		if lastHistoryRequest == nil || lastHistoryRequest.Cursor == "" {
+			return nil, fmt.Errorf("no more history")
+		}
+		return lastHistoryRequest, nil
+	}
+
+	request := &proto.HistoryRequest{
+		Room:     currentRoom(),
+		PageSize: historyPageSize,
+	}
+	for _, arg := range strings.Fields(args) {
+		key, value, _ := strings.Cut(arg, ":")
+		var err error
+		switch key {
+		case "from":
+			request.Author = value
+		case "since":
+			request.MinLamport, err = strconv.ParseInt(value, 10, 64)
+		case "until":
+			request.MaxLamport, err = strconv.ParseInt(value, 10, 64)
+		default:
+			err = fmt.Errorf("unknown filter")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("usage: /history [more] [from:<callsign>] [since:<lamport>] [until:<lamport>]")
+		}
+	}
+	return request, nil
+}
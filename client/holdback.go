@@ -0,0 +1,165 @@
+package main
+
+import (
+	"example/chittychat/clock"
+	proto "example/chittychat/grpc"
+	"log"
+	"sort"
+)
+
+// Messages held back for longer than this are delivered anyway.
+// A message lost upstream (e.g. to a feed overflow) would otherwise block the queue forever.
+const holdbackLimit = 100
+
+// Decides when received messages are handed on to be displayed.
+type deliveryQueue interface {
+	// Accepts a message from the stream and returns the messages that are now deliverable, in order.
+	push(message *proto.Message) []*proto.Message
+}
+
+// Obtains the delivery queue for a delivery mode given on the command line.
+func newDeliveryQueue(mode string) deliveryQueue {
+	switch mode {
+	case "fifo":
+		return fifoQueue{}
+	case "causal":
+		return &holdbackQueue{}
+	case "total":
+		return &totalOrderQueue{}
+	default:
+		log.Fatalf("Unknown delivery mode '%s'", mode)
+		return nil
+	}
+}
+
+// Delivers messages in the order they arrive on the stream.
+type fifoQueue struct{}
+
+func (fifoQueue) push(message *proto.Message) []*proto.Message {
+	return []*proto.Message{message}
+}
+
+// Causal delivery. A message is held back until every message it causally depends on
+// has been delivered, judged by its vector timestamp against those already delivered.
+//
+// The first message on a stream is the server's welcome. Its vector covers everything
+// broadcast before we joined, which we will never receive, so it becomes the starting point.
+type holdbackQueue struct {
+	delivered clock.Vector
+	pending   []*proto.Message
+}
+
+func (q *holdbackQueue) push(message *proto.Message) []*proto.Message {
+	if q.delivered == nil {
+		q.delivered = clock.Vector(message.VectorTs).Copy()
+		return []*proto.Message{message}
+	}
+
+	q.pending = append(q.pending, message)
+	ready := q.drain()
+	if len(q.pending) > holdbackLimit {
+		oldest := q.pending[0]
+		log.Printf("Warning: Giving up waiting for the causal predecessors of %s. Delivering anyway.\n", formatVector(oldest.VectorTs))
+		q.pending = q.pending[1:]
+		q.deliver(oldest)
+		ready = append(ready, oldest)
+		ready = append(ready, q.drain()...)
+	}
+	return ready
+}
+
+// Removes and returns deliverable messages from pending until none are left.
+// Delivering one message can make others deliverable, so the scan restarts after each.
+func (q *holdbackQueue) drain() []*proto.Message {
+	var ready []*proto.Message
+	for i := 0; i < len(q.pending); i++ {
+		message := q.pending[i]
+		if q.isDeliverable(message) {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			q.deliver(message)
+			ready = append(ready, message)
+			i = -1
+		}
+	}
+	return ready
+}
+
+// A message from author a with vector V is deliverable once it is the next message from a
+// (V[a] is one past what was delivered from a) and we have delivered everything a had seen
+// from others (V[k] <= delivered[k]). Server announcements carry no entry of their own,
+// so for them only the second condition applies. Messages without a vector are not held back.
+func (q *holdbackQueue) isDeliverable(message *proto.Message) bool {
+	for author, time := range message.VectorTs {
+		if author == message.Author {
+			if time > q.delivered[author]+1 {
+				return false
+			}
+		} else if time > q.delivered[author] {
+			return false
+		}
+	}
+	return true
+}
+
+// Records a message as delivered.
+func (q *holdbackQueue) deliver(message *proto.Message) {
+	q.delivered.Merge(message.VectorTs)
+}
+
+// Total-order delivery. Messages are delivered by the sequence number the server assigns,
+// so every client in this mode shows the exact same transcript from the point it joined.
+// A message arriving ahead of its turn is held back until the gap before it is filled.
+//
+// The first message on a stream is the server's welcome, carrying the last sequence
+// number assigned before we joined. Messages without a sequence number are not held back.
+type totalOrderQueue struct {
+	next    uint64
+	started bool
+	pending []*proto.Message
+}
+
+func (q *totalOrderQueue) push(message *proto.Message) []*proto.Message {
+	if !q.started {
+		q.started = true
+		q.next = message.Sequence + 1
+		return []*proto.Message{message}
+	}
+	if message.Sequence == 0 {
+		return []*proto.Message{message}
+	}
+	if message.Sequence < q.next {
+		return nil // Already delivered.
+	}
+
+	q.pending = append(q.pending, message)
+	sort.Slice(q.pending, func(i, j int) bool {
+		return totalOrderLess(q.pending[i], q.pending[j])
+	})
+	if len(q.pending) > holdbackLimit {
+		log.Printf("Warning: Giving up waiting for messages %d to %d. Skipping ahead.\n", q.next, q.pending[0].Sequence-1)
+		q.next = q.pending[0].Sequence
+	}
+
+	var ready []*proto.Message
+	for len(q.pending) > 0 && q.pending[0].Sequence <= q.next {
+		if q.pending[0].Sequence == q.next {
+			ready = append(ready, q.pending[0])
+			q.next++
+		}
+		q.pending = q.pending[1:]
+	}
+	return ready
+}
+
+// Total order on messages: by sequence number, then Lamport time, then author.
+// The server assigns sequence numbers in (Lamport time, author) order, so the later
+// keys only decide between messages that share a sequence number, e.g. a duplicate.
+func totalOrderLess(a, b *proto.Message) bool {
+	if a.Sequence != b.Sequence {
+		return a.Sequence < b.Sequence
+	}
+	if a.LamportTs != b.LamportTs {
+		return a.LamportTs < b.LamportTs
+	}
+	return a.Author < b.Author
+}
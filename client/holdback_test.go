@@ -0,0 +1,270 @@
+package main
+
+import (
+	proto "example/chittychat/grpc"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// Obtains a broadcast with a sequence number.
+func sequenced(sequence uint64) *proto.Message {
+	return &proto.Message{
+		Payload:  &proto.Message_Content{Content: fmt.Sprint(sequence)},
+		Author:   "alice",
+		Sequence: sequence,
+	}
+}
+
+// Obtains the sequence numbers of messages.
+func sequences(messages []*proto.Message) []uint64 {
+	var numbers []uint64
+	for _, message := range messages {
+		numbers = append(numbers, message.Sequence)
+	}
+	return numbers
+}
+
+func TestTotalOrderQueue(t *testing.T) {
+	tests := []struct {
+		name   string
+		pushes []uint64 // The first is the welcome.
+		want   [][]uint64
+	}{
+		{"in order", []uint64{5, 6, 7}, [][]uint64{{5}, {6}, {7}}},
+		{"gap", []uint64{5, 7, 8, 6}, [][]uint64{{5}, nil, nil, {6, 7, 8}}},
+		{"duplicate delivered", []uint64{5, 6, 6, 7}, [][]uint64{{5}, {6}, nil, {7}}},
+		{"duplicate held back", []uint64{5, 7, 7, 6}, [][]uint64{{5}, nil, nil, {6, 7}}},
+		{"before the welcome", []uint64{5, 3, 6}, [][]uint64{{5}, nil, {6}}},
+		{"unsequenced", []uint64{5, 7, 0, 6}, [][]uint64{{5}, nil, {0}, {6, 7}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := &totalOrderQueue{}
+			for i, sequence := range test.pushes {
+				got := sequences(q.push(sequenced(sequence)))
+				if !slices.Equal(got, test.want[i]) {
+					t.Fatalf("push %d of %d: got %v, want %v", i, sequence, got, test.want[i])
+				}
+			}
+		})
+	}
+}
+
+// A message that never arrives blocks the queue until holdbackLimit messages wait behind it.
+func TestTotalOrderQueueSkipsAhead(t *testing.T) {
+	q := &totalOrderQueue{}
+	q.push(sequenced(1))
+	var got []uint64
+	for sequence := uint64(3); sequence <= holdbackLimit+3; sequence++ {
+		got = append(got, sequences(q.push(sequenced(sequence)))...)
+	}
+	want := make([]uint64, 0, holdbackLimit+1)
+	for sequence := uint64(3); sequence <= holdbackLimit+3; sequence++ {
+		want = append(want, sequence)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if late := q.push(sequenced(2)); late != nil {
+		t.Fatalf("got %v for the skipped message, want nothing", sequences(late))
+	}
+}
+
+// Participants receiving the same broadcasts in different orders, some more than once,
+// all show the same transcript.
+func TestTotalOrderQueueTranscripts(t *testing.T) {
+	const broadcasts = 50
+	random := rand.New(rand.NewSource(1))
+	var want []uint64
+	for sequence := uint64(1); sequence <= broadcasts; sequence++ {
+		want = append(want, sequence)
+	}
+
+	for participant := 0; participant < 5; participant++ {
+		var stream []*proto.Message
+		for sequence := uint64(1); sequence <= broadcasts; sequence++ {
+			stream = append(stream, sequenced(sequence))
+			if random.Intn(4) == 0 {
+				stream = append(stream, sequenced(sequence))
+			}
+		}
+		random.Shuffle(len(stream), func(i, j int) { stream[i], stream[j] = stream[j], stream[i] })
+
+		q := &totalOrderQueue{}
+		q.push(sequenced(0)) // The welcome, before any broadcast.
+		var got []uint64
+		for _, message := range stream {
+			got = append(got, sequences(q.push(message))...)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("participant %d: got %v, want %v", participant, got, want)
+		}
+	}
+}
+
+// Obtains a message from an author with a vector timestamp.
+func stamped(author string, content string, vector map[string]int64) *proto.Message {
+	return &proto.Message{
+		Payload:  &proto.Message_Content{Content: content},
+		Author:   author,
+		VectorTs: vector,
+	}
+}
+
+// Obtains the contents of messages.
+func contents(messages []*proto.Message) []string {
+	var texts []string
+	for _, message := range messages {
+		texts = append(texts, message.GetContent())
+	}
+	return texts
+}
+
+// Calls visit with every permutation of the numbers below n.
+func permutations(n int, visit func([]int)) {
+	var permute func(order []int, k int)
+	permute = func(order []int, k int) {
+		if k == len(order) {
+			visit(order)
+			return
+		}
+		for i := k; i < len(order); i++ {
+			order[k], order[i] = order[i], order[k]
+			permute(order, k+1)
+			order[k], order[i] = order[i], order[k]
+		}
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	permute(order, 0)
+}
+
+// A conversation, with what each message causally depends on. Bob answers Alice, and Alice
+// answers Bob; Carol posts concurrently, and the server announces after Alice's answer.
+var conversation = []struct {
+	message *proto.Message
+	after   []string
+}{
+	{stamped("alice", "a1", map[string]int64{"alice": 1}), nil},
+	{stamped("bob", "b1", map[string]int64{"alice": 1, "bob": 1}), []string{"a1"}},
+	{stamped("alice", "a2", map[string]int64{"alice": 2, "bob": 1}), []string{"a1", "b1"}},
+	{stamped("carol", "c1", map[string]int64{"carol": 1}), nil},
+	{stamped("ChittyServer", "s1", map[string]int64{"alice": 2, "bob": 1}), []string{"a1", "b1", "a2"}},
+}
+
+// Every order the conversation can arrive in is delivered in full, each message after
+// those it depends on.
+func TestHoldbackQueuePermutations(t *testing.T) {
+	permutations(len(conversation), func(order []int) {
+		q := &holdbackQueue{}
+		q.push(stamped("ChittyServer", "welcome", map[string]int64{}))
+		var delivered []string
+		for _, i := range order {
+			delivered = append(delivered, contents(q.push(conversation[i].message))...)
+		}
+		if len(delivered) != len(conversation) {
+			t.Fatalf("order %v: delivered %v, want all %d", order, delivered, len(conversation))
+		}
+		for _, c := range conversation {
+			at := slices.Index(delivered, c.message.GetContent())
+			for _, before := range c.after {
+				if slices.Index(delivered, before) > at {
+					t.Fatalf("order %v: delivered %v, %s before %s", order, delivered, c.message.GetContent(), before)
+				}
+			}
+		}
+	})
+}
+
+func TestHoldbackQueue(t *testing.T) {
+	tests := []struct {
+		name    string
+		welcome map[string]int64
+		pushes  []*proto.Message
+		want    [][]string
+	}{
+		{
+			"in causal order",
+			map[string]int64{},
+			[]*proto.Message{conversation[0].message, conversation[1].message, conversation[2].message},
+			[][]string{{"a1"}, {"b1"}, {"a2"}},
+		},
+		{
+			"answer before question",
+			map[string]int64{},
+			[]*proto.Message{conversation[1].message, conversation[2].message, conversation[0].message},
+			[][]string{nil, nil, {"a1", "b1", "a2"}},
+		},
+		{
+			"announcement held back",
+			map[string]int64{},
+			[]*proto.Message{conversation[4].message, conversation[3].message, conversation[0].message, conversation[1].message, conversation[2].message},
+			[][]string{nil, {"c1"}, {"a1"}, {"b1"}, {"a2", "s1"}},
+		},
+		{
+			"covered by the welcome",
+			map[string]int64{"alice": 1},
+			[]*proto.Message{conversation[2].message, conversation[1].message},
+			[][]string{nil, {"b1", "a2"}},
+		},
+		{
+			"without a vector",
+			map[string]int64{},
+			[]*proto.Message{conversation[1].message, stamped("dave", "d1", nil)},
+			[][]string{nil, {"d1"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := &holdbackQueue{}
+			q.push(stamped("ChittyServer", "welcome", test.welcome))
+			for i, message := range test.pushes {
+				got := contents(q.push(message))
+				if !slices.Equal(got, test.want[i]) {
+					t.Fatalf("push %d of %s: got %v, want %v", i, message.GetContent(), got, test.want[i])
+				}
+			}
+		})
+	}
+}
+
+// A message whose predecessor never arrives is delivered anyway once holdbackLimit
+// messages wait behind it, and those waiting on it follow.
+func TestHoldbackQueueGivesUp(t *testing.T) {
+	q := &holdbackQueue{}
+	q.push(stamped("ChittyServer", "welcome", map[string]int64{}))
+	orphan := stamped("alice", "a2", map[string]int64{"alice": 2})
+	if got := q.push(orphan); got != nil {
+		t.Fatalf("got %v for a message after a missing one, want nothing", contents(got))
+	}
+	var want []string
+	for n := int64(1); n <= holdbackLimit; n++ {
+		message := stamped("bob", fmt.Sprintf("b%d", n), map[string]int64{"alice": 2, "bob": n})
+		got := q.push(message)
+		if n < holdbackLimit && got != nil {
+			t.Fatalf("got %v before the limit, want nothing", contents(got))
+		}
+		want = append(want, message.GetContent())
+		if n == holdbackLimit {
+			want = append([]string{"a2"}, want...)
+			if !slices.Equal(contents(got), want) {
+				t.Fatalf("got %v at the limit, want %v", contents(got), want)
+			}
+		}
+	}
+	if len(q.pending) != 0 {
+		t.Fatalf("%d message(s) still held back", len(q.pending))
+	}
+}
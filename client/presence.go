@@ -0,0 +1,33 @@
+package main
+
+import (
+	proto "example/chittychat/grpc"
+	"fmt"
+	"strings"
+)
+
+// Lists the participants on the server with the room each is in, when they joined it
+// on its clock, and their status unless they are online.
+func listParticipants(client proto.ChittyChatServiceClient) error {
+	list, err := client.ListParticipants(callContext(), &proto.Empty{})
+	if err != nil {
+		return err
+	}
+	for _, p := range list.Participants {
+		line := fmt.Sprintf("%s in %s since Lamport time %d", p.Callsign, p.Room, p.JoinedLamport)
+		if p.Status != proto.Presence_ONLINE {
+			line += " (" + strings.ToLower(p.Status.String()) + ")"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// Sets our status as others see it in /who.
+func setStatus(client proto.ChittyChatServiceClient, presence proto.Presence) error {
+	_, err := client.SetStatus(callContext(), &proto.Participant{Callsign: name, Status: presence})
+	if err == nil {
+		notify("You are %s.", strings.ToLower(presence.String()))
+	}
+	return err
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"example/chittychat/clock"
+	proto "example/chittychat/grpc"
+	"fmt"
+	"sync"
+)
+
+// Our clocks, one per room. Each room is a conversation of its own with its own clock
+// on the server, so a clock only takes in the messages of its room, and our posts in
+// one room never claim to depend on messages the others there cannot see.
+type roomClocks struct {
+	mu     sync.Mutex
+	kind   string
+	id     string
+	byRoom map[string]clock.Clock
+}
+
+// Obtains the clocks of a participant, of a kind given on the command line.
+func newRoomClocks(kind string, id string) (*roomClocks, error) {
+	_, err := clock.New(kind, id) // Fail early on an unknown kind.
+	if err != nil {
+		return nil, err
+	}
+	return &roomClocks{
+		kind:   kind,
+		id:     id,
+		byRoom: make(map[string]clock.Clock),
+	}, nil
+}
+
+// Obtains our clock in a room, starting one if we have not been there before.
+func (c *roomClocks) in(room string) clock.Clock {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clk := c.byRoom[room]
+	if clk == nil {
+		clk, _ = clock.New(c.kind, c.id)
+		c.byRoom[room] = clk
+	}
+	return clk
+}
+
+// The room we are in, or are moving to. Set from -room on startup.
+var activeRoom struct {
+	sync.Mutex
+	name string
+}
+
+// Obtains the room we are in.
+func currentRoom() string {
+	activeRoom.Lock()
+	defer activeRoom.Unlock()
+	return activeRoom.name
+}
+
+// Sets the room we are in.
+func setCurrentRoom(name string) {
+	activeRoom.Lock()
+	defer activeRoom.Unlock()
+	activeRoom.name = name
+}
+
+// A delivery queue that starts over whenever the stream moves to another room.
+// Each room has its own clock and sequence numbers, and the first message from a
+// room is its welcome, so a fresh queue takes it as the starting point.
+// Replayed messages are from before the welcome, and are shown as they come.
+type roomQueue struct {
+	mode  string
+	room  string
+	queue deliveryQueue
+}
+
+// Obtains a delivery queue for a delivery mode given on the command line.
+func newRoomQueue(mode string) *roomQueue {
+	return &roomQueue{mode: mode}
+}
+
+func (q *roomQueue) push(message *proto.Message) []*proto.Message {
+	if q.queue == nil || message.Room != q.room {
+		if q.queue != nil {
+			notify("You are now in %s.", message.Room)
+		}
+		q.room = message.Room
+		q.queue = newDeliveryQueue(q.mode)
+		lastSequence.Store(0)
+		lastVector, lastHybrid = nil, clock.Timestamp{}
+	}
+	if message.Replayed {
+		return []*proto.Message{message} // Already covered by the welcome.
+	}
+	return q.queue.push(message)
+}
+
+// Obtains our clock in the room we are in.
+func currentClock() clock.Clock {
+	return clocks.in(currentRoom())
+}
+
+// Lists the rooms on the server.
+func listRooms(client proto.ChittyChatServiceClient) error {
+	list, err := client.ListRooms(callContext(), &proto.Empty{})
+	if err != nil {
+		return err
+	}
+	for _, r := range list.Rooms {
+		marker := " "
+		if r.Name == currentRoom() {
+			marker = "*"
+		}
+		fmt.Printf("%s %s (%d)\n", marker, r.Name, r.Participants)
+	}
+	return nil
+}
+
+// Creates a room on the server.
+func createRoom(client proto.ChittyChatServiceClient, name string) error {
+	_, err := client.CreateRoom(callContext(), &proto.Room{Name: name})
+	if err == nil {
+		notify("Created room %s. Type /join %s to go there.", name, name)
+	}
+	return err
+}
+
+// Moves our stream to another room. Messages from the old room still on the way are
+// shown as they arrive, and the stream then continues with the new room's welcome.
+func joinRoom(client proto.ChittyChatServiceClient, target string) error {
+	confirm := &proto.Confirm{
+		Author: name,
+		Room:   target,
+	}
+	confirm.SetTimestamp(clocks.in(target).Now())
+	reply, err := client.JoinRoom(callContext(), confirm)
+	if err != nil {
+		return err
+	}
+	clocks.in(target).Witness(reply.Timestamp())
+	setCurrentRoom(target)
+	return nil
+}
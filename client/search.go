@@ -0,0 +1,82 @@
+package main
+
+import (
+	proto "example/chittychat/grpc"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Number of messages /search shows around each match.
+const searchContext = 2
+
+// Searches the messages of every room, and writes the matches to the log with the messages
+// around them. The arguments are words and "quoted phrases" to look for, and the filters
+// room:<room>, from:<callsign>, since:<lamport> and until:<lamport>.
+func searchMessages(client proto.ChittyChatServiceClient, args string) error {
+	request, err := searchRequest(args)
+	if err != nil {
+		return err
+	}
+	response, err := client.SearchMessages(callContext(), request)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range response.Results {
+		log.Printf("(search) in %s:\n", result.Message.Room)
+		for _, message := range result.Before {
+			log.Printf("(search)      %d %s\n", message.LamportTs, formatPayload(message))
+		}
+		log.Printf("(search)   >> %d %s\n", result.Message.LamportTs, formatPayload(result.Message))
+		for _, message := range result.After {
+			log.Printf("(search)      %d %s\n", message.LamportTs, formatPayload(message))
+		}
+	}
+	notify("%d match(es) in your log.", len(response.Results))
+	return nil
+}
+
+// Parses the arguments of /search into a request. Filters are taken out of the query
+// wherever they are, unless inside quotes.
+func searchRequest(args string) (*proto.SearchRequest, error) {
+	request := &proto.SearchRequest{Context: searchContext}
+	var query []string
+	quoted := false
+	for _, arg := range strings.Fields(args) {
+		if key, value, found := strings.Cut(arg, ":"); found && !quoted {
+			isFilter, err := applySearchFilter(request, key, value)
+			if err != nil {
+				return nil, fmt.Errorf("usage: /search <words or \"phrase\"> [room:<room>] [from:<callsign>] [since:<lamport>] [until:<lamport>]")
+			}
+			if isFilter {
+				continue
+			}
+		}
+		query = append(query, arg)
+		if strings.Count(arg, "\"")%2 == 1 {
+			quoted = !quoted
+		}
+	}
+	request.Query = strings.Join(query, " ")
+	return request, nil
+}
+
+// Sets the filter of a search request named by key. Reports whether key names a filter.
+func applySearchFilter(request *proto.SearchRequest, key string, value string) (bool, error) {
+	var err error
+	switch key {
+	case "room":
+		request.Room = value
+	case "from":
+		request.Author = value
+	case "since":
+		request.MinLamport, err = strconv.ParseInt(value, 10, 64)
+	case "until":
+		request.MaxLamport, err = strconv.ParseInt(value, 10, 64)
+	default:
+		return false, nil
+	}
+	return true, err
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Obtains the transport credentials for connecting to the server.
+// Without a CA file, the connection is plaintext. With one, the server's certificate
+// must be signed by it. A client certificate and key are presented for mutual TLS,
+// which needs a CA file: they would go unused on a plaintext connection.
+func transportCredentials(caFile string, certFile string, keyFile string, serverName string) (credentials.TransportCredentials, error) {
+	if caFile == "" && (certFile != "" || keyFile != "") {
+		return nil, errors.New("a client certificate needs -tls-ca, to connect with TLS")
+	}
+	if caFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates in %s", caFile)
+	}
+	config := &tls.Config{
+		RootCAs:    pool,
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// Obtains the common name of a certificate. Under mutual TLS, it is our callsign.
+func certificateName(certFile string, keyFile string) (string, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return "", err
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return "", err
+	}
+	return leaf.Subject.CommonName, nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestTransportCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		caFile   string
+		certFile string
+		keyFile  string
+		ok       bool
+	}{
+		{"plaintext", "", "", "", true},
+		{"certificate without a CA", "", "alice.crt", "alice.key", false},
+		{"key without a CA", "", "", "alice.key", false},
+		{"missing CA file", "missing-ca.crt", "", "", false},
+	}
+	for _, test := range tests {
+		creds, err := transportCredentials(test.caFile, test.certFile, test.keyFile, "")
+		if (err == nil) != test.ok {
+			t.Errorf("%s: got %v, want ok: %v", test.name, err, test.ok)
+		}
+		if err == nil && creds.Info().SecurityProtocol != "insecure" {
+			t.Errorf("%s: got %s credentials, want plaintext", test.name, creds.Info().SecurityProtocol)
+		}
+	}
+}
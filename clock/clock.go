@@ -0,0 +1,52 @@
+// Package clock provides the logical clocks used by ChittyChat servers and participants.
+// All clocks are safe for concurrent use.
+package clock
+
+import "fmt"
+
+// A reading of a logical clock.
+// Every clock keeps a Lamport time. Vector and hybrid clocks also fill in their own fields.
+type Timestamp struct {
+	Lamport int64
+	Vector  Vector
+	Wall    int64 // Physical component of a hybrid timestamp, in Unix nanoseconds.
+	Logical int64 // Logical component of a hybrid timestamp.
+}
+
+// A logical clock.
+type Clock interface {
+	// Advances the clock for a local or send event and returns the new time.
+	Tick() Timestamp
+	// Merges a timestamp received from elsewhere into the clock, without advancing it.
+	// The next Tick is then guaranteed to come after the witnessed timestamp.
+	Witness(in Timestamp)
+	// Returns the current time without advancing the clock.
+	Now() Timestamp
+	// Returns the time the next Tick would return, without advancing the clock.
+	// Witnessing it later has the effect of that Tick, so a send event can be stamped
+	// before it happens, and only recorded once it has.
+	Next() Timestamp
+}
+
+// Names of the clock strategies accepted by New.
+const (
+	LamportKind = "lamport"
+	VectorKind  = "vector"
+	HybridKind  = "hybrid"
+)
+
+// Obtains a clock of the given kind for the process with the given id.
+// For a vector clock, an empty id gives a relay that merges what it witnesses
+// but owns no entry of its own.
+func New(kind string, id string) (Clock, error) {
+	switch kind {
+	case LamportKind:
+		return &Lamport{}, nil
+	case VectorKind:
+		return NewVector(id), nil
+	case HybridKind:
+		return NewHybrid(), nil
+	default:
+		return nil, fmt.Errorf("unknown clock '%s' (want %s, %s or %s)", kind, LamportKind, VectorKind, HybridKind)
+	}
+}
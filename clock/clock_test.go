@@ -0,0 +1,86 @@
+package clock
+
+import (
+	"sync"
+	"testing"
+)
+
+// Obtains a clock of every kind.
+func allClocks(t *testing.T) map[string]Clock {
+	clocks := make(map[string]Clock)
+	for _, kind := range []string{LamportKind, VectorKind, HybridKind} {
+		c, err := New(kind, "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		clocks[kind] = c
+	}
+	return clocks
+}
+
+// Checks that two timestamps are the same in every field.
+func sameTimestamp(a Timestamp, b Timestamp) bool {
+	return a.Lamport == b.Lamport && a.Wall == b.Wall && a.Logical == b.Logical && a.Vector.Compare(b.Vector) == Equal
+}
+
+func TestNewUnknown(t *testing.T) {
+	if _, err := New("sundial", "alice"); err == nil {
+		t.Fatal("got a clock of an unknown kind")
+	}
+}
+
+// Next leaves the clock alone, and witnessing what it returned has the effect of a tick.
+func TestNextThenWitness(t *testing.T) {
+	for kind, c := range allClocks(t) {
+		c.Tick()
+		c.Witness(Timestamp{Lamport: 7, Vector: Vector{"bob": 2}})
+		before := c.Now()
+		next := c.Next()
+		if again := c.Next(); !sameTimestamp(c.Now(), before) || next.Lamport != again.Lamport {
+			t.Fatalf("%s: Next moved the clock from %v to %v", kind, before, c.Now())
+		}
+		if next.Lamport != before.Lamport+1 {
+			t.Fatalf("%s: got Lamport %d from Next, want %d", kind, next.Lamport, before.Lamport+1)
+		}
+		c.Witness(next)
+		if now := c.Now(); !sameTimestamp(now, next) {
+			t.Fatalf("%s: at %v after witnessing %v, want the same", kind, now, next)
+		}
+		if ts := c.Tick(); ts.Lamport != next.Lamport+1 {
+			t.Fatalf("%s: ticked to Lamport %d after %d", kind, ts.Lamport, next.Lamport)
+		}
+	}
+}
+
+// Ticks and witnesses from many go-routines are neither lost nor torn. Run with -race.
+func TestConcurrentTickWitness(t *testing.T) {
+	const routines = 8
+	const ticks = 500
+	for kind, c := range allClocks(t) {
+		var wg sync.WaitGroup
+		for i := 0; i < routines; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for n := 0; n < ticks; n++ {
+					c.Tick()
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for n := 0; n < ticks; n++ {
+					c.Witness(Timestamp{Vector: Vector{"bob": int64(n)}})
+					c.Now()
+				}
+			}()
+		}
+		wg.Wait()
+		now := c.Now()
+		if now.Lamport != routines*ticks {
+			t.Fatalf("%s: at Lamport %d after %d ticks", kind, now.Lamport, routines*ticks)
+		}
+		if kind == VectorKind && (now.Vector["alice"] != routines*ticks || now.Vector["bob"] != ticks-1) {
+			t.Fatalf("%s: at %v, want <alice:%d bob:%d>", kind, now.Vector, routines*ticks, ticks-1)
+		}
+	}
+}
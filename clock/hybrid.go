@@ -0,0 +1,88 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// A hybrid logical clock (Kulkarni et al.). The wall component follows the
+// physical clock where it can, and the logical counter orders events that share
+// a wall time, so timestamps stay causally ordered even when physical clocks drift.
+// It also keeps a Lamport time, so messages carry a scalar timestamp whatever the clock.
+type HybridClock struct {
+	mu      sync.Mutex
+	lamport Lamport
+	wall    int64
+	logical int64
+	now     func() time.Time
+}
+
+// Obtains a hybrid logical clock reading the system clock.
+func NewHybrid() *HybridClock {
+	return &HybridClock{now: time.Now}
+}
+
+func (c *HybridClock) Tick() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.wall, c.logical = c.next()
+	ts := c.lamport.Tick()
+	ts.Wall, ts.Logical = c.wall, c.logical
+	return ts
+}
+
+func (c *HybridClock) Next() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ts := c.lamport.Next()
+	ts.Wall, ts.Logical = c.next()
+	return ts
+}
+
+// Obtains the hybrid part of the next tick.
+// The caller must hold c.mu.
+func (c *HybridClock) next() (wall int64, logical int64) {
+	physical := c.now().UnixNano()
+	if physical > c.wall {
+		return physical, 0
+	}
+	return c.wall, c.logical + 1
+}
+
+func (c *HybridClock) Witness(in Timestamp) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lamport.Witness(in)
+	if in.Wall > c.wall {
+		c.wall, c.logical = in.Wall, in.Logical
+	} else if in.Wall == c.wall && in.Logical > c.logical {
+		c.logical = in.Logical
+	}
+}
+
+func (c *HybridClock) Now() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ts := c.lamport.Now()
+	ts.Wall, ts.Logical = c.wall, c.logical
+	return ts
+}
+
+// Reports whether the timestamp has a hybrid part.
+func (t Timestamp) IsHybrid() bool {
+	return t.Wall != 0
+}
+
+// Obtains the physical time of a hybrid timestamp.
+func (t Timestamp) WallTime() time.Time {
+	return time.Unix(0, t.Wall)
+}
+
+// Reports whether t comes before other in hybrid logical clock order:
+// by wall time, then by logical counter.
+func (t Timestamp) HybridBefore(other Timestamp) bool {
+	if t.Wall != other.Wall {
+		return t.Wall < other.Wall
+	}
+	return t.Logical < other.Logical
+}
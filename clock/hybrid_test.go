@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// Obtains a hybrid clock reading a physical time the test sets.
+func fakeHybrid(physical *int64) *HybridClock {
+	return &HybridClock{now: func() time.Time { return time.Unix(0, *physical) }}
+}
+
+// Every event comes after the one before it and after whatever was witnessed, whether the
+// physical clock is ahead, behind, or has not moved.
+func TestHybridClock(t *testing.T) {
+	physical := int64(100)
+	c := fakeHybrid(&physical)
+	steps := []struct {
+		physical int64
+		witness  *Timestamp
+		want     Timestamp // Of the tick after.
+	}{
+		{100, nil, Timestamp{Wall: 100, Logical: 0}},
+		{100, nil, Timestamp{Wall: 100, Logical: 1}},
+		{100, &Timestamp{Wall: 500, Logical: 3}, Timestamp{Wall: 500, Logical: 4}},
+		{200, &Timestamp{Wall: 500, Logical: 2}, Timestamp{Wall: 500, Logical: 5}},
+		{200, &Timestamp{Wall: 300, Logical: 9}, Timestamp{Wall: 500, Logical: 6}},
+		{1000, nil, Timestamp{Wall: 1000, Logical: 0}},
+	}
+	var last Timestamp
+	for i, step := range steps {
+		physical = step.physical
+		if step.witness != nil {
+			c.Witness(*step.witness)
+		}
+		ts := c.Tick()
+		if ts.Wall != step.want.Wall || ts.Logical != step.want.Logical {
+			t.Fatalf("step %d: got %d.%d, want %d.%d", i, ts.Wall, ts.Logical, step.want.Wall, step.want.Logical)
+		}
+		if !last.HybridBefore(ts) {
+			t.Fatalf("step %d: %d.%d does not come after %d.%d", i, ts.Wall, ts.Logical, last.Wall, last.Logical)
+		}
+		if step.witness != nil && !step.witness.HybridBefore(ts) {
+			t.Fatalf("step %d: %d.%d does not come after the witnessed %d.%d", i, ts.Wall, ts.Logical, step.witness.Wall, step.witness.Logical)
+		}
+		last = ts
+	}
+}
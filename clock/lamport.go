@@ -0,0 +1,43 @@
+package clock
+
+import "sync"
+
+// A Lamport clock: a single counter, ticked on every event and pushed forward by
+// every timestamp witnessed.
+type Lamport struct {
+	mu   sync.Mutex
+	time int64
+}
+
+func (c *Lamport) Tick() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.time++
+	return Timestamp{Lamport: c.time}
+}
+
+func (c *Lamport) Witness(in Timestamp) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.witness(in.Lamport)
+}
+
+func (c *Lamport) Now() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Timestamp{Lamport: c.time}
+}
+
+func (c *Lamport) Next() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Timestamp{Lamport: c.time + 1}
+}
+
+// Updates the Lamport time to reflect an incoming timestamp.
+// The caller must hold c.mu.
+func (c *Lamport) witness(in int64) {
+	if c.time < in {
+		c.time = in
+	}
+}
@@ -0,0 +1,114 @@
+package clock
+
+import "sync"
+
+// A vector timestamp, keyed by process id. Missing entries count as zero.
+type Vector map[string]int64
+
+// Causal relation between two vector timestamps.
+type Ordering int
+
+const (
+	Equal Ordering = iota
+	Before
+	After
+	Concurrent
+)
+
+// Returns a copy of the vector.
+func (v Vector) Copy() Vector {
+	out := make(Vector, len(v))
+	for id, time := range v {
+		out[id] = time
+	}
+	return out
+}
+
+// Takes the entry-wise maximum of v and in, storing it in v.
+func (v Vector) Merge(in Vector) {
+	for id, time := range in {
+		if v[id] < time {
+			v[id] = time
+		}
+	}
+}
+
+// Compares v to other: Before means v happened before other.
+func (v Vector) Compare(other Vector) Ordering {
+	vLess, otherLess := false, false
+	for id, time := range v {
+		if time < other[id] {
+			vLess = true
+		} else if time > other[id] {
+			otherLess = true
+		}
+	}
+	for id, time := range other {
+		if _, ok := v[id]; !ok && time > 0 {
+			vLess = true
+		}
+	}
+	switch {
+	case vLess && otherLess:
+		return Concurrent
+	case vLess:
+		return Before
+	case otherLess:
+		return After
+	default:
+		return Equal
+	}
+}
+
+// A vector clock. Ticking advances the entry of the owning process.
+// It also keeps a Lamport time, so messages carry a scalar timestamp whatever the clock.
+type VectorClock struct {
+	mu      sync.Mutex
+	id      string
+	lamport Lamport
+	vector  Vector
+}
+
+// Obtains a vector clock for the process with the given id.
+// With an empty id, the clock is a relay: it merges the vectors it witnesses,
+// but ticking only advances its Lamport time.
+func NewVector(id string) *VectorClock {
+	return &VectorClock{id: id, vector: make(Vector)}
+}
+
+func (c *VectorClock) Tick() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.id != "" {
+		c.vector[c.id]++
+	}
+	ts := c.lamport.Tick()
+	ts.Vector = c.vector.Copy()
+	return ts
+}
+
+func (c *VectorClock) Witness(in Timestamp) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lamport.Witness(in)
+	c.vector.Merge(in.Vector)
+}
+
+func (c *VectorClock) Next() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ts := c.lamport.Next()
+	ts.Vector = c.vector.Copy()
+	if c.id != "" {
+		ts.Vector[c.id]++
+	}
+	return ts
+}
+
+func (c *VectorClock) Now() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ts := c.lamport.Now()
+	ts.Vector = c.vector.Copy()
+	return ts
+}
@@ -0,0 +1,52 @@
+package clock
+
+import "testing"
+
+func TestVectorCompare(t *testing.T) {
+	tests := []struct {
+		name  string
+		v     Vector
+		other Vector
+		want  Ordering
+	}{
+		{"equal", Vector{"a": 1, "b": 2}, Vector{"a": 1, "b": 2}, Equal},
+		{"both empty", Vector{}, nil, Equal},
+		{"zero entry", Vector{"a": 1, "b": 0}, Vector{"a": 1}, Equal},
+		{"before", Vector{"a": 1, "b": 1}, Vector{"a": 1, "b": 2}, Before},
+		{"after", Vector{"a": 2, "b": 2}, Vector{"a": 1, "b": 2}, After},
+		{"concurrent", Vector{"a": 2, "b": 1}, Vector{"a": 1, "b": 2}, Concurrent},
+		{"missing in v", Vector{"a": 1}, Vector{"a": 1, "b": 1}, Before},
+		{"missing in other", Vector{"a": 1, "b": 1}, Vector{"a": 1}, After},
+		{"missing on both sides", Vector{"a": 1}, Vector{"b": 1}, Concurrent},
+		{"empty before", Vector{}, Vector{"a": 1}, Before},
+	}
+	for _, test := range tests {
+		if got := test.v.Compare(test.other); got != test.want {
+			t.Errorf("%s: %v compared to %v is %v, want %v", test.name, test.v, test.other, got, test.want)
+		}
+	}
+}
+
+func TestVectorClock(t *testing.T) {
+	c := NewVector("alice")
+	c.Tick()
+	c.Witness(Timestamp{Lamport: 5, Vector: Vector{"bob": 3, "alice": 1}})
+	ts := c.Tick()
+	if ts.Lamport != 6 || ts.Vector.Compare(Vector{"alice": 2, "bob": 3}) != Equal {
+		t.Fatalf("got %v after witnessing bob, want Lamport 6 and <alice:2 bob:3>", ts)
+	}
+	ts.Vector["alice"] = 100
+	if now := c.Now(); now.Vector["alice"] != 2 {
+		t.Fatalf("changing a timestamp changed the clock to %v", now)
+	}
+}
+
+// A relay merges the vectors it witnesses, but has no entry of its own to advance.
+func TestVectorClockRelay(t *testing.T) {
+	c := NewVector("")
+	c.Witness(Timestamp{Lamport: 2, Vector: Vector{"alice": 2}})
+	ts := c.Tick()
+	if ts.Lamport != 3 || ts.Vector.Compare(Vector{"alice": 2}) != Equal {
+		t.Fatalf("got %v, want Lamport 3 and <alice:2>", ts)
+	}
+}
@@ -0,0 +1,123 @@
+// Package config fills in command-line flags from environment variables and a config file.
+//
+// A flag given on the command line wins. Otherwise the flag is taken from an environment
+// variable, named by a prefix followed by the flag name in upper case with dashes turned into
+// underscores (e.g. CHITTYCHAT_SERVER_DRAIN_TIMEOUT for -drain-timeout). Otherwise it is taken
+// from the config file, a JSON object keyed by flag name. Flags found nowhere keep their default.
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Name of the flag giving the config file. A flag set must define it to be read from a file.
+const FileFlag = "config"
+
+// Fills in the flags of a parsed flag set that were not given on the command line, from
+// environment variables starting with prefix and then from the file named by the -config flag.
+// The config file can itself be named by an environment variable.
+func Load(fs *flag.FlagSet, prefix string) error {
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		value, ok := os.LookupEnv(EnvName(prefix, f.Name))
+		if err != nil || given[f.Name] || !ok {
+			return
+		}
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("%s: %v", EnvName(prefix, f.Name), setErr)
+		}
+		given[f.Name] = true
+	})
+	if err != nil {
+		return err
+	}
+
+	file := fs.Lookup(FileFlag)
+	if file == nil || file.Value.String() == "" {
+		return nil
+	}
+	values, err := readFile(file.Value.String())
+	if err != nil {
+		return err
+	}
+	for name, value := range values {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("%s: unknown setting '%s'", file.Value, name)
+		}
+		if given[name] {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("%s: %s: %v", file.Value, name, err)
+		}
+	}
+	return nil
+}
+
+// Obtains the environment variable name for a flag.
+func EnvName(prefix string, flagName string) string {
+	return prefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// Reads a config file. Values may be JSON strings, numbers or booleans,
+// and are returned as the text a flag would be set to.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	values := make(map[string]string, len(raw))
+	for name, value := range raw {
+		switch value := value.(type) {
+		case string:
+			values[name] = value
+		case float64:
+			values[name] = strconv.FormatFloat(value, 'f', -1, 64)
+		case bool:
+			values[name] = strconv.FormatBool(value)
+		default:
+			return nil, fmt.Errorf("%s: %s: want a string, number or boolean", path, name)
+		}
+	}
+	return values, nil
+}
+
+// Shown instead of the value of a secret flag that is set.
+const redacted = "<redacted>"
+
+// Writes the effective value of every flag as a JSON object, which can be used as a config file.
+// The flags named in omit (e.g. -print-config itself) are left out, and the values of those
+// named in secret are replaced with a placeholder.
+func Print(w io.Writer, fs *flag.FlagSet, omit []string, secret []string) error {
+	values := make(map[string]string)
+	fs.VisitAll(func(f *flag.Flag) {
+		values[f.Name] = f.Value.String()
+	})
+	for _, name := range omit {
+		delete(values, name)
+	}
+	for _, name := range secret {
+		if values[name] != "" {
+			values[name] = redacted
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(values)
+}
@@ -3,6 +3,8 @@ module example/chittychat
 go 1.23.2
 
 require (
+	golang.org/x/crypto v0.26.0
+	golang.org/x/term v0.23.0
 	google.golang.org/grpc v1.67.1
 	google.golang.org/protobuf v1.35.1
 )
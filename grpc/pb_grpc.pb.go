@@ -19,18 +19,59 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ChittyChatService_PostMessage_FullMethodName      = "/ChittyChatService/PostMessage"
-	ChittyChatService_JoinMessageBoard_FullMethodName = "/ChittyChatService/JoinMessageBoard"
+	ChittyChatService_PostMessage_FullMethodName       = "/ChittyChatService/PostMessage"
+	ChittyChatService_JoinMessageBoard_FullMethodName  = "/ChittyChatService/JoinMessageBoard"
+	ChittyChatService_LeaveMessageBoard_FullMethodName = "/ChittyChatService/LeaveMessageBoard"
+	ChittyChatService_CreateRoom_FullMethodName        = "/ChittyChatService/CreateRoom"
+	ChittyChatService_ListRooms_FullMethodName         = "/ChittyChatService/ListRooms"
+	ChittyChatService_JoinRoom_FullMethodName          = "/ChittyChatService/JoinRoom"
+	ChittyChatService_SendDirectMessage_FullMethodName = "/ChittyChatService/SendDirectMessage"
+	ChittyChatService_GetHistory_FullMethodName        = "/ChittyChatService/GetHistory"
+	ChittyChatService_SearchMessages_FullMethodName    = "/ChittyChatService/SearchMessages"
+	ChittyChatService_ListParticipants_FullMethodName  = "/ChittyChatService/ListParticipants"
+	ChittyChatService_WatchPresence_FullMethodName     = "/ChittyChatService/WatchPresence"
+	ChittyChatService_SetStatus_FullMethodName         = "/ChittyChatService/SetStatus"
+	ChittyChatService_Chat_FullMethodName              = "/ChittyChatService/Chat"
 )
 
 // ChittyChatServiceClient is the client API for ChittyChatService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ChittyChatServiceClient interface {
-	// A client can publish a message to the service
+	// Broadcast a message to all clients in the sender's room.
 	PostMessage(ctx context.Context, in *Message, opts ...grpc.CallOption) (*Confirm, error)
-	// The server can broadcast a recieved message to all participants (including the original sender?)
+	// Obtain a stream of messages from server, for the room in the confirm.
 	JoinMessageBoard(ctx context.Context, in *Confirm, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Message], error)
+	// Leave the chat, ending the stream from JoinMessageBoard.
+	// The message carries the participant's clock and an optional farewell as content.
+	LeaveMessageBoard(ctx context.Context, in *Message, opts ...grpc.CallOption) (*Confirm, error)
+	// Create a room with the given name.
+	CreateRoom(ctx context.Context, in *Room, opts ...grpc.CallOption) (*Room, error)
+	// List the rooms, with the number of participants in each.
+	ListRooms(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RoomList, error)
+	// Move the stream from JoinMessageBoard to the room in the confirm.
+	// The stream continues with the room's welcome message.
+	JoinRoom(ctx context.Context, in *Confirm, opts ...grpc.CallOption) (*Confirm, error)
+	// Send a message to one participant only, named by the recipient.
+	// It is delivered to the recipient's stream and echoed to the sender's.
+	SendDirectMessage(ctx context.Context, in *Message, opts ...grpc.CallOption) (*Confirm, error)
+	// Obtain earlier messages of a room in pages, newest page first.
+	// Does not need a stream from JoinMessageBoard.
+	GetHistory(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryPage, error)
+	// Search the messages broadcast in every room for words and "quoted phrases".
+	// Does not need a stream from JoinMessageBoard.
+	SearchMessages(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// List the participants holding a callsign, in every room.
+	ListParticipants(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ParticipantList, error)
+	// Obtain a stream of presence events. It starts with a JOINED event for
+	// every participant already there.
+	WatchPresence(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PresenceEvent], error)
+	// Set the caller's status to ONLINE or AWAY.
+	SetStatus(ctx context.Context, in *Participant, opts ...grpc.CallOption) (*Empty, error)
+	// Chat on a single stream. The first request joins the message board, as with
+	// JoinMessageBoard, and the rest post messages, as with PostMessage. The responses
+	// are the stream of messages, with an acknowledgement of each post in between.
+	Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatRequest, ChatResponse], error)
 }
 
 type chittyChatServiceClient struct {
@@ -70,14 +111,166 @@ func (c *chittyChatServiceClient) JoinMessageBoard(ctx context.Context, in *Conf
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ChittyChatService_JoinMessageBoardClient = grpc.ServerStreamingClient[Message]
 
+func (c *chittyChatServiceClient) LeaveMessageBoard(ctx context.Context, in *Message, opts ...grpc.CallOption) (*Confirm, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Confirm)
+	err := c.cc.Invoke(ctx, ChittyChatService_LeaveMessageBoard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chittyChatServiceClient) CreateRoom(ctx context.Context, in *Room, opts ...grpc.CallOption) (*Room, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Room)
+	err := c.cc.Invoke(ctx, ChittyChatService_CreateRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chittyChatServiceClient) ListRooms(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RoomList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomList)
+	err := c.cc.Invoke(ctx, ChittyChatService_ListRooms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chittyChatServiceClient) JoinRoom(ctx context.Context, in *Confirm, opts ...grpc.CallOption) (*Confirm, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Confirm)
+	err := c.cc.Invoke(ctx, ChittyChatService_JoinRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chittyChatServiceClient) SendDirectMessage(ctx context.Context, in *Message, opts ...grpc.CallOption) (*Confirm, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Confirm)
+	err := c.cc.Invoke(ctx, ChittyChatService_SendDirectMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chittyChatServiceClient) GetHistory(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoryPage)
+	err := c.cc.Invoke(ctx, ChittyChatService_GetHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chittyChatServiceClient) SearchMessages(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, ChittyChatService_SearchMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chittyChatServiceClient) ListParticipants(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ParticipantList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ParticipantList)
+	err := c.cc.Invoke(ctx, ChittyChatService_ListParticipants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chittyChatServiceClient) WatchPresence(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PresenceEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChittyChatService_ServiceDesc.Streams[1], ChittyChatService_WatchPresence_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Empty, PresenceEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChittyChatService_WatchPresenceClient = grpc.ServerStreamingClient[PresenceEvent]
+
+func (c *chittyChatServiceClient) SetStatus(ctx context.Context, in *Participant, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChittyChatService_SetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chittyChatServiceClient) Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatRequest, ChatResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChittyChatService_ServiceDesc.Streams[2], ChittyChatService_Chat_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ChatRequest, ChatResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChittyChatService_ChatClient = grpc.BidiStreamingClient[ChatRequest, ChatResponse]
+
 // ChittyChatServiceServer is the server API for ChittyChatService service.
 // All implementations must embed UnimplementedChittyChatServiceServer
 // for forward compatibility.
 type ChittyChatServiceServer interface {
-	// A client can publish a message to the service
+	// Broadcast a message to all clients in the sender's room.
 	PostMessage(context.Context, *Message) (*Confirm, error)
-	// The server can broadcast a recieved message to all participants (including the original sender?)
+	// Obtain a stream of messages from server, for the room in the confirm.
 	JoinMessageBoard(*Confirm, grpc.ServerStreamingServer[Message]) error
+	// Leave the chat, ending the stream from JoinMessageBoard.
+	// The message carries the participant's clock and an optional farewell as content.
+	LeaveMessageBoard(context.Context, *Message) (*Confirm, error)
+	// Create a room with the given name.
+	CreateRoom(context.Context, *Room) (*Room, error)
+	// List the rooms, with the number of participants in each.
+	ListRooms(context.Context, *Empty) (*RoomList, error)
+	// Move the stream from JoinMessageBoard to the room in the confirm.
+	// The stream continues with the room's welcome message.
+	JoinRoom(context.Context, *Confirm) (*Confirm, error)
+	// Send a message to one participant only, named by the recipient.
+	// It is delivered to the recipient's stream and echoed to the sender's.
+	SendDirectMessage(context.Context, *Message) (*Confirm, error)
+	// Obtain earlier messages of a room in pages, newest page first.
+	// Does not need a stream from JoinMessageBoard.
+	GetHistory(context.Context, *HistoryRequest) (*HistoryPage, error)
+	// Search the messages broadcast in every room for words and "quoted phrases".
+	// Does not need a stream from JoinMessageBoard.
+	SearchMessages(context.Context, *SearchRequest) (*SearchResponse, error)
+	// List the participants holding a callsign, in every room.
+	ListParticipants(context.Context, *Empty) (*ParticipantList, error)
+	// Obtain a stream of presence events. It starts with a JOINED event for
+	// every participant already there.
+	WatchPresence(*Empty, grpc.ServerStreamingServer[PresenceEvent]) error
+	// Set the caller's status to ONLINE or AWAY.
+	SetStatus(context.Context, *Participant) (*Empty, error)
+	// Chat on a single stream. The first request joins the message board, as with
+	// JoinMessageBoard, and the rest post messages, as with PostMessage. The responses
+	// are the stream of messages, with an acknowledgement of each post in between.
+	Chat(grpc.BidiStreamingServer[ChatRequest, ChatResponse]) error
 	mustEmbedUnimplementedChittyChatServiceServer()
 }
 
@@ -94,6 +287,39 @@ func (UnimplementedChittyChatServiceServer) PostMessage(context.Context, *Messag
 func (UnimplementedChittyChatServiceServer) JoinMessageBoard(*Confirm, grpc.ServerStreamingServer[Message]) error {
 	return status.Errorf(codes.Unimplemented, "method JoinMessageBoard not implemented")
 }
+func (UnimplementedChittyChatServiceServer) LeaveMessageBoard(context.Context, *Message) (*Confirm, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaveMessageBoard not implemented")
+}
+func (UnimplementedChittyChatServiceServer) CreateRoom(context.Context, *Room) (*Room, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRoom not implemented")
+}
+func (UnimplementedChittyChatServiceServer) ListRooms(context.Context, *Empty) (*RoomList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRooms not implemented")
+}
+func (UnimplementedChittyChatServiceServer) JoinRoom(context.Context, *Confirm) (*Confirm, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinRoom not implemented")
+}
+func (UnimplementedChittyChatServiceServer) SendDirectMessage(context.Context, *Message) (*Confirm, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendDirectMessage not implemented")
+}
+func (UnimplementedChittyChatServiceServer) GetHistory(context.Context, *HistoryRequest) (*HistoryPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (UnimplementedChittyChatServiceServer) SearchMessages(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchMessages not implemented")
+}
+func (UnimplementedChittyChatServiceServer) ListParticipants(context.Context, *Empty) (*ParticipantList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListParticipants not implemented")
+}
+func (UnimplementedChittyChatServiceServer) WatchPresence(*Empty, grpc.ServerStreamingServer[PresenceEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPresence not implemented")
+}
+func (UnimplementedChittyChatServiceServer) SetStatus(context.Context, *Participant) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetStatus not implemented")
+}
+func (UnimplementedChittyChatServiceServer) Chat(grpc.BidiStreamingServer[ChatRequest, ChatResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Chat not implemented")
+}
 func (UnimplementedChittyChatServiceServer) mustEmbedUnimplementedChittyChatServiceServer() {}
 func (UnimplementedChittyChatServiceServer) testEmbeddedByValue()                           {}
 
@@ -144,6 +370,186 @@ func _ChittyChatService_JoinMessageBoard_Handler(srv interface{}, stream grpc.Se
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ChittyChatService_JoinMessageBoardServer = grpc.ServerStreamingServer[Message]
 
+func _ChittyChatService_LeaveMessageBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Message)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChittyChatServiceServer).LeaveMessageBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChittyChatService_LeaveMessageBoard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChittyChatServiceServer).LeaveMessageBoard(ctx, req.(*Message))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChittyChatService_CreateRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Room)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChittyChatServiceServer).CreateRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChittyChatService_CreateRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChittyChatServiceServer).CreateRoom(ctx, req.(*Room))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChittyChatService_ListRooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChittyChatServiceServer).ListRooms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChittyChatService_ListRooms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChittyChatServiceServer).ListRooms(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChittyChatService_JoinRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Confirm)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChittyChatServiceServer).JoinRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChittyChatService_JoinRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChittyChatServiceServer).JoinRoom(ctx, req.(*Confirm))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChittyChatService_SendDirectMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Message)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChittyChatServiceServer).SendDirectMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChittyChatService_SendDirectMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChittyChatServiceServer).SendDirectMessage(ctx, req.(*Message))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChittyChatService_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChittyChatServiceServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChittyChatService_GetHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChittyChatServiceServer).GetHistory(ctx, req.(*HistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChittyChatService_SearchMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChittyChatServiceServer).SearchMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChittyChatService_SearchMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChittyChatServiceServer).SearchMessages(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChittyChatService_ListParticipants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChittyChatServiceServer).ListParticipants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChittyChatService_ListParticipants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChittyChatServiceServer).ListParticipants(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChittyChatService_WatchPresence_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChittyChatServiceServer).WatchPresence(m, &grpc.GenericServerStream[Empty, PresenceEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChittyChatService_WatchPresenceServer = grpc.ServerStreamingServer[PresenceEvent]
+
+func _ChittyChatService_SetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Participant)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChittyChatServiceServer).SetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChittyChatService_SetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChittyChatServiceServer).SetStatus(ctx, req.(*Participant))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChittyChatService_Chat_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ChittyChatServiceServer).Chat(&grpc.GenericServerStream[ChatRequest, ChatResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChittyChatService_ChatServer = grpc.BidiStreamingServer[ChatRequest, ChatResponse]
+
 // ChittyChatService_ServiceDesc is the grpc.ServiceDesc for ChittyChatService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -155,6 +561,42 @@ var ChittyChatService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PostMessage",
 			Handler:    _ChittyChatService_PostMessage_Handler,
 		},
+		{
+			MethodName: "LeaveMessageBoard",
+			Handler:    _ChittyChatService_LeaveMessageBoard_Handler,
+		},
+		{
+			MethodName: "CreateRoom",
+			Handler:    _ChittyChatService_CreateRoom_Handler,
+		},
+		{
+			MethodName: "ListRooms",
+			Handler:    _ChittyChatService_ListRooms_Handler,
+		},
+		{
+			MethodName: "JoinRoom",
+			Handler:    _ChittyChatService_JoinRoom_Handler,
+		},
+		{
+			MethodName: "SendDirectMessage",
+			Handler:    _ChittyChatService_SendDirectMessage_Handler,
+		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _ChittyChatService_GetHistory_Handler,
+		},
+		{
+			MethodName: "SearchMessages",
+			Handler:    _ChittyChatService_SearchMessages_Handler,
+		},
+		{
+			MethodName: "ListParticipants",
+			Handler:    _ChittyChatService_ListParticipants_Handler,
+		},
+		{
+			MethodName: "SetStatus",
+			Handler:    _ChittyChatService_SetStatus_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -162,6 +604,17 @@ var ChittyChatService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _ChittyChatService_JoinMessageBoard_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchPresence",
+			Handler:       _ChittyChatService_WatchPresence_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Chat",
+			Handler:       _ChittyChatService_Chat_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "grpc/pb.proto",
 }
@@ -0,0 +1,45 @@
+package proto
+
+import "example/chittychat/clock"
+
+// Reads the logical timestamp carried by the message.
+func (x *Message) Timestamp() clock.Timestamp {
+	return clock.Timestamp{
+		Lamport: x.GetLamportTs(),
+		Vector:  x.GetVectorTs(),
+		Wall:    x.GetHlcTs().GetWallTime(),
+		Logical: x.GetHlcTs().GetLogical(),
+	}
+}
+
+// Stamps the message with a logical timestamp.
+func (x *Message) SetTimestamp(ts clock.Timestamp) {
+	x.LamportTs = ts.Lamport
+	x.VectorTs = ts.Vector
+	x.HlcTs = hybridTimestamp(ts)
+}
+
+// Reads the logical timestamp carried by the confirm.
+func (x *Confirm) Timestamp() clock.Timestamp {
+	return clock.Timestamp{
+		Lamport: x.GetLamportTs(),
+		Vector:  x.GetVectorTs(),
+		Wall:    x.GetHlcTs().GetWallTime(),
+		Logical: x.GetHlcTs().GetLogical(),
+	}
+}
+
+// Stamps the confirm with a logical timestamp.
+func (x *Confirm) SetTimestamp(ts clock.Timestamp) {
+	x.LamportTs = ts.Lamport
+	x.VectorTs = ts.Vector
+	x.HlcTs = hybridTimestamp(ts)
+}
+
+// Obtains the hybrid part of a timestamp, or nil if the clock is not hybrid.
+func hybridTimestamp(ts clock.Timestamp) *HybridTimestamp {
+	if !ts.IsHybrid() {
+		return nil
+	}
+	return &HybridTimestamp{WallTime: ts.Wall, Logical: ts.Logical}
+}